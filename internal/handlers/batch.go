@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/calendar"
+	"github.com/heckin-dev/amashan/pkg/limiter"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxBatchCharacters is the most characters Batch looks up per request, a guild roster's worth.
+	maxBatchCharacters = 100
+	// batchConcurrency caps how many characters of a batch are looked up at once.
+	batchConcurrency = 8
+	// batchTimeout bounds a whole batch, the response being streamed for longer than the server's write timeout.
+	batchTimeout = 2 * time.Minute
+)
+
+// batchCharacter is a character of a Batch request.
+type batchCharacter struct {
+	Region string `json:"region"`
+	Realm  string `json:"realm"`
+	Name   string `json:"name"`
+}
+
+type batchRequest struct {
+	Characters []batchCharacter `json:"characters"`
+	Sections   []string         `json:"sections"`
+}
+
+// batchResult is a line of the Batch response, the sections of a character that could be looked up.
+type batchResult struct {
+	// Index is the position of the character in the request, as results are streamed in the order they complete.
+	Index int `json:"index"`
+	batchCharacter
+	Sections map[string]json.RawMessage `json:"sections"`
+	// Errors are the sections that couldn't be looked up, keyed by the section.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// batchSection looks up a section of a character, cached under the same key & for the same duration as the endpoint
+// serving it on its own.
+type batchSection struct {
+	key      func(c batchCharacter) string
+	duration time.Duration
+	// staleAt, when set, is when the character's section goes stale, which it's never cached past.
+	staleAt func(c batchCharacter) time.Time
+	fetch   func(ctx context.Context, c batchCharacter) (any, error)
+}
+
+// Batch looks up many characters at once through the same caches & clients as the character endpoints.
+type Batch struct {
+	l hclog.Logger
+
+	sections map[string]*batchSection
+}
+
+// Characters streams the requested sections of each character as NDJSON, a line per character as they complete.
+func (b *Batch) Characters(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+
+	req, err := b.batchRequest(http.MaxBytesReader(w, r.Body, 1<<18))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(limiter.WithPriority(r.Context(), limiter.Bulk), batchTimeout)
+	defer cancel()
+
+	results := make(chan *batchResult)
+	sem := make(chan struct{}, batchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, c := range req.Characters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results <- b.resolve(ctx, cache, i, c, req.Sections)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(batchTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		b.l.Warn("failed to extend the write deadline of the batch", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for result := range results {
+		// Keep draining the results once the client has gone, the lookups fail fast on the cancelled context.
+		if err := enc.Encode(result); err != nil {
+			cancel()
+			continue
+		}
+		_ = rc.Flush()
+	}
+}
+
+// resolve looks up the sections of a character, from the cache where they're cached.
+func (b *Batch) resolve(ctx context.Context, cache middleware.CacheClient, index int, c batchCharacter, sections []string) *batchResult {
+	result := &batchResult{
+		Index:          index,
+		batchCharacter: c,
+		Sections:       map[string]json.RawMessage{},
+	}
+
+	for _, name := range sections {
+		section := b.sections[name]
+		key := section.key(c)
+
+		// Cache HIT
+		if val, err := cache.Get(ctx, key); err == nil {
+			result.Sections[name] = json.RawMessage(val)
+			continue
+		}
+
+		v, err := section.fetch(ctx, c)
+		if err == nil {
+			var bs []byte
+			if bs, err = json.Marshal(v); err == nil {
+				duration := section.duration
+				if section.staleAt != nil {
+					duration = min(duration, time.Until(section.staleAt(c)))
+				}
+
+				// Cache SET
+				go cache.Set(key, string(bs), duration)
+				result.Sections[name] = bs
+				continue
+			}
+		}
+
+		b.l.Warn("failed to look up a section of the batch", "section", name, "region", c.Region, "realm", c.Realm, "character", c.Name, "error", err)
+		if result.Errors == nil {
+			result.Errors = map[string]string{}
+		}
+		result.Errors[name] = fmt.Sprintf("failed to look up %s", name)
+	}
+
+	return result
+}
+
+// batchRequest reads & validates a Batch request body.
+func (b *Batch) batchRequest(body io.Reader) (*batchRequest, error) {
+	var req batchRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, errors.New("request body must be a json object with a list of 'characters' & 'sections'")
+	}
+
+	if len(req.Characters) == 0 || len(req.Characters) > maxBatchCharacters {
+		return nil, fmt.Errorf("request body must have between 1-%d 'characters'", maxBatchCharacters)
+	}
+
+	for i, c := range req.Characters {
+		var err error
+		if c.Region, c.Realm, c.Name, err = middleware.ValidateCharacter(c.Region, c.Realm, c.Name); err != nil {
+			return nil, fmt.Errorf("characters[%d] %w", i, err)
+		}
+
+		req.Characters[i] = c
+	}
+
+	if len(req.Sections) == 0 {
+		return nil, errors.New("request body must have at least one of 'sections'")
+	}
+
+	var sections []string
+	for _, s := range req.Sections {
+		s = strings.ToLower(s)
+		if _, ok := b.sections[s]; !ok {
+			return nil, fmt.Errorf("section '%s' is not one of %s", s, strings.Join(b.sectionNames(), ", "))
+		}
+		if !slices.Contains(sections, s) {
+			sections = append(sections, s)
+		}
+	}
+	req.Sections = sections
+
+	return &req, nil
+}
+
+// sectionNames are the names of the sections a Batch can look up, sorted.
+func (b *Batch) sectionNames() []string {
+	names := make([]string, 0, len(b.sections))
+	for name := range b.sections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func (b *Batch) Route(r *mux.Router) {
+	r.HandleFunc("/characters/batch", b.Characters).Methods(http.MethodPost)
+}
+
+// characterOptions are the Battle.net options of a batch character.
+func (c batchCharacter) characterOptions() *bnet.CharacterOptions {
+	return &bnet.CharacterOptions{Region: c.Region, Realm: c.Realm, Character: c.Name}
+}
+
+// characterPath is the path of the character's Battle.net endpoints, which their responses are cached under.
+func (c batchCharacter) characterPath(suffix string) string {
+	return fmt.Sprintf("/api/%s/wow/%s/%s%s", c.Region, c.Realm, c.Name, suffix)
+}
+
+func NewBatch(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO, greatVault *GreatVault) *Batch {
+	return &Batch{
+		l: l,
+		sections: map[string]*batchSection{
+			"summary": {
+				key:      func(c batchCharacter) string { return c.characterPath("") },
+				duration: 15 * time.Minute,
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return battleNet.client.CharacterSummary(ctx, c.characterOptions())
+				},
+			},
+			"equipment": {
+				key:      func(c batchCharacter) string { return c.characterPath("/equipment") },
+				duration: 15 * time.Minute,
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return battleNet.client.CharacterEquipmentSummary(ctx, c.characterOptions())
+				},
+			},
+			"media": {
+				key:      func(c batchCharacter) string { return c.characterPath("/character-media") },
+				duration: 15 * time.Minute,
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return battleNet.client.CharacterMedia(ctx, c.characterOptions())
+				},
+			},
+			"statistics": {
+				key:      func(c batchCharacter) string { return c.characterPath("/character-statistics") },
+				duration: 15 * time.Minute,
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return battleNet.client.CharacterStatistics(ctx, c.characterOptions())
+				},
+			},
+			"great-vault": {
+				key:      func(c batchCharacter) string { return c.characterPath("/great-vault") },
+				duration: 5 * time.Minute,
+				staleAt: func(c batchCharacter) time.Time {
+					nextResetAt, _ := calendar.NextWeeklyReset(c.Region, time.Now())
+					return nextResetAt
+				},
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return greatVault.tracker.Vault(ctx, &analysis.VaultOptions{Region: c.Region, Realm: c.Realm, Character: c.Name})
+				},
+			},
+			"raiderio": {
+				key:      func(c batchCharacter) string { return fmt.Sprintf("/api/raiderio/%s/%s/%s", c.Region, c.Realm, c.Name) },
+				duration: 5 * time.Minute,
+				fetch: func(ctx context.Context, c batchCharacter) (any, error) {
+					return raiderIO.client.CharacterProfile(ctx, &rio.CharacterProfileOptions{Region: c.Region, Realm: c.Realm, Character: c.Name})
+				},
+			},
+		},
+	}
+}
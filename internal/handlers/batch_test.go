@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatch_BatchRequest(t *testing.T) {
+	b := NewBatch(hclog.NewNullLogger(), NewBattleNet(hclog.NewNullLogger()), NewRaiderIO(hclog.NewNullLogger()), &GreatVault{})
+
+	tooMany := strings.TrimSuffix(strings.Repeat(`{"region":"us","realm":"illidan","name":"skxyz"},`, maxBatchCharacters+1), ",")
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *batchRequest
+		wantErr string
+	}{
+		{
+			name: "lower-cased & deduplicated",
+			body: `{"characters":[{"region":"US","realm":"Illidan","name":"Skxyz"}],"sections":["summary","RaiderIO","summary"]}`,
+			want: &batchRequest{
+				Characters: []batchCharacter{{Region: "us", Realm: "illidan", Name: "skxyz"}},
+				Sections:   []string{"summary", "raiderio"},
+			},
+		},
+		{name: "not json", body: `[]`, wantErr: "request body must be a json object with a list of 'characters' & 'sections'"},
+		{name: "no characters", body: `{"sections":["summary"]}`, wantErr: "request body must have between 1-100 'characters'"},
+		{name: "too many characters", body: fmt.Sprintf(`{"characters":[%s],"sections":["summary"]}`, tooMany), wantErr: "request body must have between 1-100 'characters'"},
+		{name: "bad region", body: `{"characters":[{"region":"cn","realm":"illidan","name":"skxyz"}],"sections":["summary"]}`, wantErr: "characters[0] region 'cn' is not a supported region"},
+		{name: "no sections", body: `{"characters":[{"region":"us","realm":"illidan","name":"skxyz"}]}`, wantErr: "request body must have at least one of 'sections'"},
+		{
+			name:    "unknown section",
+			body:    `{"characters":[{"region":"us","realm":"illidan","name":"skxyz"}],"sections":["parses"]}`,
+			wantErr: "section 'parses' is not one of equipment, great-vault, media, raiderio, statistics, summary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := b.batchRequest(strings.NewReader(tt.body))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, req)
+		})
+	}
+}
+
+func TestBatch_Characters(t *testing.T) {
+	b := &Batch{
+		l: hclog.NewNullLogger(),
+		sections: map[string]*batchSection{
+			"summary": {
+				key:      func(c batchCharacter) string { return c.characterPath("") },
+				duration: time.Minute,
+				fetch: func(_ context.Context, c batchCharacter) (any, error) {
+					if c.Name == "missing" {
+						return nil, errors.New("not found")
+					}
+					return map[string]string{"name": c.Name}, nil
+				},
+			},
+		},
+	}
+
+	body := `{"characters":[{"region":"us","realm":"illidan","name":"skxyz"},{"region":"eu","realm":"draenor","name":"missing"}],"sections":["summary"]}`
+	req := httptest.NewRequest(http.MethodPost, "/characters/batch", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.CacheContextKey, missCache{}))
+
+	rr := httptest.NewRecorder()
+	b.Characters(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+
+	results := map[int]*batchResult{}
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		result := &batchResult{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), result))
+		results[result.Index] = result
+	}
+	assert.Len(t, results, 2)
+
+	assert.JSONEq(t, `{"name":"skxyz"}`, string(results[0].Sections["summary"]))
+	assert.Empty(t, results[0].Errors)
+
+	assert.Equal(t, "draenor", results[1].Realm)
+	assert.Empty(t, results[1].Sections)
+	assert.Equal(t, map[string]string{"summary": "failed to look up summary"}, results[1].Errors)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/calendar"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Calendar serves the weekly reset, mythic keystone period & season of a region.
+type Calendar struct {
+	l hclog.Logger
+
+	calendar *calendar.Calendar
+}
+
+func (c *Calendar) Calendar(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+
+	// The calendar can include China, which the rest of the API doesn't support.
+	region := strings.ToLower(mux.Vars(r)["region"])
+	if !slices.Contains(calendar.Regions, region) {
+		http.Error(w, fmt.Sprintf("region '%s' is not a supported region", region), http.StatusBadRequest)
+		return
+	}
+
+	key := fmt.Sprintf("/api/%s/wow/calendar", region)
+
+	// Never cache the calendar past the reset.
+	nextResetAt, _ := calendar.NextWeeklyReset(region, time.Now())
+	duration := min(time.Hour, time.Until(nextResetAt))
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	cal, err := c.calendar.Region(r.Context(), region)
+	if err != nil {
+		c.l.Error("failed to work out the calendar", "region", region, "error", err)
+		http.Error(w, "failed to work out the calendar", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the Calendar
+	bs, err := json.Marshal(cal)
+	if err != nil {
+		c.l.Error("json.Marshal failed for Calendar", "error", err)
+		http.Error(w, "failed to marshal calendar", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (c *Calendar) Route(r *mux.Router) {
+	r.HandleFunc("/{region}/wow/calendar", c.Calendar).Methods(http.MethodGet)
+}
+
+func NewCalendar(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO) *Calendar {
+	return &Calendar{
+		l:        l,
+		calendar: calendar.NewCalendar(l, battleNet.client, raiderIO.client),
+	}
+}
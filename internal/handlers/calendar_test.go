@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCalendar_Calendar_Validation(t *testing.T) {
+	battleNet, raiderIO := NewBattleNet(hclog.NewNullLogger()), NewRaiderIO(hclog.NewNullLogger())
+
+	// The calendar shares its routes' prefix with the BattleNet regional routes.
+	sm := mux.NewRouter()
+	sm.Use(withMissCache)
+	battleNet.Route(sm)
+	NewCalendar(hclog.NewNullLogger(), battleNet, raiderIO).Route(sm)
+
+	req := httptest.NewRequest(http.MethodGet, "/oc/wow/calendar", nil)
+
+	rr := httptest.NewRecorder()
+	sm.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "region 'oc' is not a supported region\n", rr.Body.String())
+}
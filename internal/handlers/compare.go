@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Compare serves characters side by side, e.g. a trial against the raiders of their role.
+type Compare struct {
+	l hclog.Logger
+
+	comparer *analysis.CharacterComparer
+}
+
+func (c *Compare) Compare(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
+
+	characters, err := comparedCharacters(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options := &analysis.OverviewOptions{}
+	if err := overviewOptions(r.URL.Query(), options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	comparison := c.comparer.Compare(r.Context(), &analysis.CompareOptions{
+		Characters: characters,
+		ZoneID:     options.ZoneID,
+	})
+
+	// Marshal the Comparison
+	bs, err := json.Marshal(comparison)
+	if err != nil {
+		c.l.Error("json.Marshal failed for Comparison", "error", err)
+		http.Error(w, "failed to marshal comparison", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Cache SET, only once every character was fetched in full so a partial comparison is retried.
+	if !comparison.Partial() {
+		go cache.Set(key, string(bs), duration)
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	}
+
+	_, _ = w.Write(bs)
+}
+
+// comparedCharacters are the characters of the 'c' query params, each a region/realm/name.
+func comparedCharacters(q url.Values) ([]analysis.CompareCharacter, error) {
+	params := q["c"]
+	if len(params) < 2 || len(params) > analysis.MaxComparedCharacters {
+		return nil, fmt.Errorf("query param 'c' must be given between 2-%d times", analysis.MaxComparedCharacters)
+	}
+
+	characters := make([]analysis.CompareCharacter, 0, len(params))
+	for i, param := range params {
+		parts := strings.Split(param, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("c[%d] must be a region/realm/name", i)
+		}
+
+		var c analysis.CompareCharacter
+		var err error
+		if c.Region, c.Realm, c.Character, err = middleware.ValidateCharacter(parts[0], parts[1], parts[2]); err != nil {
+			return nil, fmt.Errorf("c[%d] %w", i, err)
+		}
+
+		characters = append(characters, c)
+	}
+
+	return characters, nil
+}
+
+func (c *Compare) Route(r *mux.Router) {
+	r.HandleFunc("/compare", c.Compare).Methods(http.MethodGet)
+}
+
+func NewCompare(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO, warcraftLogs *WarcraftLogs) *Compare {
+	return &Compare{
+		l:        l,
+		comparer: analysis.NewCharacterComparer(l, analysis.NewOverviewAggregator(l, battleNet.client, raiderIO.client, warcraftLogs.client)),
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestComparedCharacters(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []analysis.CompareCharacter
+		wantErr string
+	}{
+		{
+			name:  "lower-cased",
+			query: "c=US/Illidan/Skxyz&c=eu/draenor/trial",
+			want: []analysis.CompareCharacter{
+				{Region: "us", Realm: "illidan", Character: "skxyz"},
+				{Region: "eu", Realm: "draenor", Character: "trial"},
+			},
+		},
+		{name: "one character", query: "c=us/illidan/skxyz", wantErr: "query param 'c' must be given between 2-5 times"},
+		{name: "too many characters", query: "c=us/a/aa&c=us/a/bb&c=us/a/cc&c=us/a/dd&c=us/a/ee&c=us/a/ff", wantErr: "query param 'c' must be given between 2-5 times"},
+		{name: "not a path", query: "c=us/illidan/skxyz&c=trial", wantErr: "c[1] must be a region/realm/name"},
+		{name: "bad region", query: "c=cn/illidan/skxyz&c=eu/draenor/trial", wantErr: "c[0] region 'cn' is not a supported region"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+
+			characters, err := comparedCharacters(q)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, characters)
+		})
+	}
+}
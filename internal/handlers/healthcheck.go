@@ -4,13 +4,46 @@ import (
 	"encoding/json"
 	"github.com/gorilla/mux"
 	"net/http"
+	"sync"
 )
 
-type Healthcheck struct{}
+// ReadinessChecker reports whether a dependency of the API is ready to serve requests.
+type ReadinessChecker interface {
+	Ready() bool
+}
+
+type Healthcheck struct {
+	mu     sync.RWMutex
+	checks map[string]ReadinessChecker
+}
+
+type HealthcheckResponse struct {
+	OK    bool            `json:"OK"`
+	Ready map[string]bool `json:"ready,omitempty"`
+}
 
 func (h *Healthcheck) GetHealthcheck(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	res := &HealthcheckResponse{OK: true}
+	if len(h.checks) > 0 {
+		res.Ready = map[string]bool{}
+		for name, check := range h.checks {
+			res.Ready[name] = check.Ready()
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]bool{"OK": true})
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Register adds the named dependency's readiness to the healthcheck.
+func (h *Healthcheck) Register(name string, check ReadinessChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check
 }
 
 func (h *Healthcheck) Route(r *mux.Router) {
@@ -18,5 +51,7 @@ func (h *Healthcheck) Route(r *mux.Router) {
 }
 
 func NewHealthcheck() *Healthcheck {
-	return &Healthcheck{}
+	return &Healthcheck{
+		checks: map[string]ReadinessChecker{},
+	}
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -37,3 +38,30 @@ func TestHealthcheck_GetHealthcheck(t *testing.T) {
 		})
 	}
 }
+
+type readiness bool
+
+func (r readiness) Ready() bool {
+	return bool(r)
+}
+
+func TestHealthcheck_Register(t *testing.T) {
+	h := NewHealthcheck()
+	h.Register("warcraftlogs", readiness(false))
+
+	req, err := http.NewRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.GetHealthcheck).ServeHTTP(rr, req)
+
+	res := &HealthcheckResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, res.OK)
+	assert.Equal(t, map[string]bool{"warcraftlogs": false}, res.Ready)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MythicPlus serves the Mythic+ tools built from both the Battle.net & Raider.IO data of a character.
+type MythicPlus struct {
+	l hclog.Logger
+
+	planner *analysis.MythicPlusPlanner
+}
+
+func (m *MythicPlus) Planner(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 15 * time.Minute
+	key := r.RequestURI
+
+	options := &analysis.PlannerOptions{
+		Region:    r.Context().Value(middleware.RegionContextKey).(string),
+		Realm:     r.Context().Value(middleware.RealmContextKey).(string),
+		Character: r.Context().Value(middleware.CharacterContextKey).(string),
+	}
+	if err := plannerOptions(r.URL.Query(), options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	plan, err := m.planner.Plan(r.Context(), options)
+	if err != nil {
+		if errors.Is(err, analysis.ErrInvalidPlannerLevels) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m.l.Error("failed to plan mythic plus keys", "error", err)
+		http.Error(w, "failed to plan mythic plus keys", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the MythicPlusPlan
+	bs, err := json.Marshal(plan)
+	if err != nil {
+		m.l.Error("json.Marshal failed for MythicPlusPlan", "error", err)
+		http.Error(w, "failed to marshal mythic plus plan", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// plannerOptions sets the optional keystone levels of the options from the query params.
+func plannerOptions(q url.Values, options *analysis.PlannerOptions) error {
+	if q.Has("min_level") {
+		level, err := strconv.Atoi(q.Get("min_level"))
+		if err != nil {
+			return errors.New("optional query param 'min_level' must be an integer")
+		}
+		options.MinLevel = &level
+	}
+
+	if q.Has("max_level") {
+		level, err := strconv.Atoi(q.Get("max_level"))
+		if err != nil {
+			return errors.New("optional query param 'max_level' must be an integer")
+		}
+		options.MaxLevel = &level
+	}
+
+	return nil
+}
+
+func (m *MythicPlus) Route(r *mux.Router) {
+	rrcRouter := r.PathPrefix("/{region}/wow/{realm}/{character}").Subrouter()
+	rrcRouter.Use(middleware.UseRegion().Middleware)
+	rrcRouter.Use(middleware.UseRealm().Middleware)
+	rrcRouter.Use(middleware.UseCharacter().Middleware)
+
+	rrcRouter.HandleFunc("/mplus-planner", m.Planner).Methods(http.MethodGet)
+}
+
+func NewMythicPlus(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO) *MythicPlus {
+	return &MythicPlus{
+		l:       l,
+		planner: analysis.NewMythicPlusPlanner(l, battleNet.client, raiderIO.client),
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// missCache is a middleware.CacheClient that never has anything cached.
+type missCache struct{}
+
+func (missCache) Get(_ context.Context, _ string) (string, error) {
+	return "", errors.New("cache miss")
+}
+
+func (missCache) Set(_, _ string, _ time.Duration) {}
+
+func (missCache) Del(_ string) {}
+
+func withMissCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.CacheContextKey, missCache{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func TestMythicPlus_Planner_Validation(t *testing.T) {
+	battleNet, raiderIO := NewBattleNet(hclog.NewNullLogger()), NewRaiderIO(hclog.NewNullLogger())
+
+	// The planner shares its routes' prefix with the BattleNet character routes.
+	sm := mux.NewRouter()
+	sm.Use(withMissCache)
+	battleNet.Route(sm)
+	NewMythicPlus(hclog.NewNullLogger(), battleNet, raiderIO).Route(sm)
+
+	tests := []struct {
+		name  string
+		path  string
+		query string
+	}{
+		{name: "bad min level", path: "/us/wow/illidan/skxyz/mplus-planner", query: "min_level=high"},
+		{name: "bad max level", path: "/us/wow/illidan/skxyz/mplus-planner", query: "max_level=12.5"},
+		{name: "bad region", path: "/cn/wow/illidan/skxyz/mplus-planner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path+"?"+tt.query, nil)
+
+			rr := httptest.NewRecorder()
+			sm.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
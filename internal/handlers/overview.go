@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Overview serves a single document of a character's Battle.net profile, Raider.IO profile & Warcraft Logs parses.
+type Overview struct {
+	l hclog.Logger
+
+	aggregator *analysis.OverviewAggregator
+}
+
+func (o *Overview) Overview(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
+
+	options := &analysis.OverviewOptions{
+		Region:    r.Context().Value(middleware.RegionContextKey).(string),
+		Realm:     r.Context().Value(middleware.RealmContextKey).(string),
+		Character: r.Context().Value(middleware.CharacterContextKey).(string),
+	}
+	if err := overviewOptions(r.URL.Query(), options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	overview := o.aggregator.Overview(r.Context(), options)
+
+	// Marshal the CharacterOverview
+	bs, err := json.Marshal(overview)
+	if err != nil {
+		o.l.Error("json.Marshal failed for CharacterOverview", "error", err)
+		http.Error(w, "failed to marshal character overview", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Nothing could be fetched, the sources say why.
+	if overview.Failed() {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write(bs)
+		return
+	}
+
+	// Cache SET, only once every section was fetched so a partial overview is retried.
+	if !overview.Partial() {
+		go cache.Set(key, string(bs), duration)
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	}
+
+	_, _ = w.Write(bs)
+}
+
+// overviewOptions sets the optional Warcraft Logs zone of the options from the query params.
+func overviewOptions(q url.Values, options *analysis.OverviewOptions) error {
+	if q.Has("zone_id") {
+		zone, err := strconv.Atoi(q.Get("zone_id"))
+		if err != nil {
+			return errors.New("optional query param 'zone_id' must be an integer")
+		}
+		options.ZoneID = &zone
+	}
+
+	return nil
+}
+
+func (o *Overview) Route(r *mux.Router) {
+	rrcRouter := r.PathPrefix("/{region}/wow/{realm}/{character}").Subrouter()
+	rrcRouter.Use(middleware.UseRegion().Middleware)
+	rrcRouter.Use(middleware.UseRealm().Middleware)
+	rrcRouter.Use(middleware.UseCharacter().Middleware)
+
+	rrcRouter.HandleFunc("/overview", o.Overview).Methods(http.MethodGet)
+}
+
+func NewOverview(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO, warcraftLogs *WarcraftLogs) *Overview {
+	return &Overview{
+		l:          l,
+		aggregator: analysis.NewOverviewAggregator(l, battleNet.client, raiderIO.client, warcraftLogs.client),
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestOverviewOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *int
+		wantErr bool
+	}{
+		{name: "no zone", query: ""},
+		{name: "zone", query: "zone_id=38", want: wl.Int(38)},
+		{name: "bad zone", query: "zone_id=nerubar", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+
+			options := &analysis.OverviewOptions{}
+			err := overviewOptions(q, options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, options.ZoneID)
+		})
+	}
+}
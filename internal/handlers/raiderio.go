@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
 	"github.com/heckin-dev/amashan/pkg/middleware"
 	"github.com/heckin-dev/amashan/pkg/rio"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,7 +24,17 @@ type RaiderIO struct {
 func (i *RaiderIO) CharacterProfile(w http.ResponseWriter, r *http.Request) {
 	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
 	duration := 5 * time.Minute
-	key := r.URL.Path
+	key := r.RequestURI
+
+	options := rio.CharacterProfileOptionsFromContext(r.Context())
+	if q := r.URL.Query(); q.Has("fields") {
+		fields, err := rio.ParseProfileFields(q.Get("fields"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("optional query param 'fields' is invalid: %s", err), http.StatusBadRequest)
+			return
+		}
+		options.Fields = fields
+	}
 
 	// Cache HIT
 	if val, err := cache.Get(r.Context(), key); err == nil {
@@ -29,7 +44,7 @@ func (i *RaiderIO) CharacterProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profile, err := i.client.CharacterProfile(r.Context(), rio.CharacterProfileOptionsFromContext(r.Context()))
+	profile, err := i.client.CharacterProfile(r.Context(), options)
 	if err != nil {
 		i.l.Error("failed to retrieve raiderio character profile", "error", err)
 		http.Error(w, "failed to retrieve raiderio character profile", http.StatusInternalServerError)
@@ -52,7 +67,512 @@ func (i *RaiderIO) CharacterProfile(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(bs)
 }
 
+func (i *RaiderIO) GuildProfile(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 15 * time.Minute
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	profile, err := i.client.GuildProfile(r.Context(), rio.GuildProfileOptionsFromContext(r.Context()))
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio guild profile", "error", err)
+		http.Error(w, "failed to retrieve raiderio guild profile", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the GuildProfile
+	bs, err := json.Marshal(profile)
+	if err != nil {
+		i.l.Error("json.Marshal failed for GuildProfile", "error", err)
+		http.Error(w, "failed to marshal raiderio guild profile", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) GuildBossKill(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 1 * time.Hour
+	key := r.RequestURI
+
+	guild := rio.GuildProfileOptionsFromContext(r.Context())
+	options := &rio.GuildBossKillOptions{
+		Region: guild.Region,
+		Realm:  guild.Realm,
+		Guild:  guild.Guild,
+	}
+	if err := guildBossKillOptions(r.URL.Query(), options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	kill, err := i.client.GuildBossKill(r.Context(), options)
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio guild boss kill", "error", err)
+		http.Error(w, "failed to retrieve raiderio guild boss kill", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the GuildBossKill
+	bs, err := json.Marshal(kill)
+	if err != nil {
+		i.l.Error("json.Marshal failed for GuildBossKill", "error", err)
+		http.Error(w, "failed to marshal raiderio guild boss kill", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) RaidingProgression(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 15 * time.Minute
+	key := r.RequestURI
+
+	options, err := raidingProgressionOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	progression, err := i.client.RaidingProgression(r.Context(), options)
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio raiding progression", "error", err)
+		http.Error(w, "failed to retrieve raiderio raiding progression", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the RaidingProgression
+	bs, err := json.Marshal(progression)
+	if err != nil {
+		i.l.Error("json.Marshal failed for RaidingProgression", "error", err)
+		http.Error(w, "failed to marshal raiderio raiding progression", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) MythicPlusAffixes(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 6 * time.Hour
+	key := r.RequestURI
+
+	options, err := affixesOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	affixes, err := i.client.MythicPlusAffixes(r.Context(), options)
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio mythic plus affixes", "error", err)
+		http.Error(w, "failed to retrieve raiderio mythic plus affixes", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the MythicPlusAffixes
+	bs, err := json.Marshal(affixes)
+	if err != nil {
+		i.l.Error("json.Marshal failed for MythicPlusAffixes", "error", err)
+		http.Error(w, "failed to marshal raiderio mythic plus affixes", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) MythicPlusStaticData(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 24 * time.Hour
+	key := r.RequestURI
+
+	expansionID, err := strconv.Atoi(r.URL.Query().Get("expansion_id"))
+	if err != nil || expansionID < 0 {
+		http.Error(w, "query param 'expansion_id' must be an integer, e.g. 10", http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	staticData, err := i.client.MythicPlusStaticData(r.Context(), &rio.StaticDataOptions{ExpansionID: expansionID})
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio mythic plus static data", "error", err)
+		http.Error(w, "failed to retrieve raiderio mythic plus static data", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the MythicPlusStaticData
+	bs, err := json.Marshal(staticData)
+	if err != nil {
+		i.l.Error("json.Marshal failed for MythicPlusStaticData", "error", err)
+		http.Error(w, "failed to marshal raiderio mythic plus static data", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) MythicPlusSeasonCutoffs(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 6 * time.Hour
+	key := r.RequestURI
+
+	options, score, err := seasonCutoffsOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	cutoffs, err := i.client.MythicPlusSeasonCutoffs(r.Context(), options)
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio mythic plus season cutoffs", "error", err)
+		http.Error(w, "failed to retrieve raiderio mythic plus season cutoffs", http.StatusInternalServerError)
+		return
+	}
+
+	dto := &rio.SeasonCutoffsDTO{SeasonCutoffsResponse: *cutoffs, Score: score}
+	if score != nil {
+		dto.Distances = cutoffs.Cutoffs.Distances(*score)
+	}
+
+	// Marshal the SeasonCutoffsDTO
+	bs, err := json.Marshal(dto)
+	if err != nil {
+		i.l.Error("json.Marshal failed for SeasonCutoffsDTO", "error", err)
+		http.Error(w, "failed to marshal raiderio mythic plus season cutoffs", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) MythicPlusRuns(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 15 * time.Minute
+	key := r.RequestURI
+
+	options, err := runsOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	runs, err := i.client.MythicPlusRuns(r.Context(), options)
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio mythic plus runs", "error", err)
+		http.Error(w, "failed to retrieve raiderio mythic plus runs", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the MythicPlusRuns
+	bs, err := json.Marshal(runs)
+	if err != nil {
+		i.l.Error("json.Marshal failed for MythicPlusRuns", "error", err)
+		http.Error(w, "failed to marshal raiderio mythic plus runs", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (i *RaiderIO) MythicPlusRunDetails(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	// Finished runs never change.
+	duration := 24 * time.Hour
+	key := r.URL.Path
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "route param 'id' must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	run, err := i.client.MythicPlusRunDetails(r.Context(), &rio.RunDetailsOptions{
+		Season: strings.ToLower(vars["season"]),
+		ID:     id,
+	})
+	if err != nil {
+		i.l.Error("failed to retrieve raiderio mythic plus run details", "error", err)
+		http.Error(w, "failed to retrieve raiderio mythic plus run details", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the MythicPlusRunDetails
+	bs, err := json.Marshal(run)
+	if err != nil {
+		i.l.Error("json.Marshal failed for MythicPlusRunDetails", "error", err)
+		http.Error(w, "failed to marshal raiderio mythic plus run details", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// runsOptions creates a RunsOptions from the query params, defaulting to the first page of the world leaderboard of
+// every dungeon & affix.
+func runsOptions(q url.Values) (*rio.RunsOptions, error) {
+	options := &rio.RunsOptions{
+		Season:  strings.ToLower(strings.TrimSpace(q.Get("season"))),
+		Region:  "world",
+		Dungeon: "all",
+		Affixes: "all",
+	}
+	if options.Season == "" {
+		return nil, errors.New("query param 'season' is required, e.g. 'season-tww-1'")
+	}
+
+	if q.Has("region") {
+		options.Region = strings.ToLower(q.Get("region"))
+		if options.Region != "world" && !slices.Contains(middleware.Regions, options.Region) {
+			return nil, fmt.Errorf("optional query param 'region' must be world or one of %s", strings.Join(middleware.Regions, ", "))
+		}
+	}
+
+	if q.Has("dungeon") {
+		options.Dungeon = strings.ToLower(strings.TrimSpace(q.Get("dungeon")))
+	}
+
+	if q.Has("affixes") {
+		options.Affixes = strings.ToLower(strings.TrimSpace(q.Get("affixes")))
+	}
+
+	if q.Has("page") {
+		page, err := strconv.Atoi(q.Get("page"))
+		if err != nil || page < 0 {
+			return nil, errors.New("optional query param 'page' must be an integer of 0 or more")
+		}
+		options.Page = page
+	}
+
+	if options.Dungeon == "" || options.Affixes == "" {
+		return nil, errors.New("optional query params 'dungeon' & 'affixes' must not be empty")
+	}
+
+	return options, nil
+}
+
+// affixesOptions creates an AffixesOptions from the query params, the region defaulting to us & the locale to en.
+func affixesOptions(q url.Values) (*rio.AffixesOptions, error) {
+	options := &rio.AffixesOptions{Region: "us", Locale: "en"}
+
+	if q.Has("region") {
+		options.Region = strings.ToLower(q.Get("region"))
+		if !slices.Contains(middleware.Regions, options.Region) {
+			return nil, fmt.Errorf("optional query param 'region' must be one of %s", strings.Join(middleware.Regions, ", "))
+		}
+	}
+
+	if q.Has("locale") {
+		options.Locale = strings.ToLower(q.Get("locale"))
+		if len(options.Locale) != 2 {
+			return nil, errors.New("optional query param 'locale' must be a two letter language, e.g. en")
+		}
+	}
+
+	return options, nil
+}
+
+// seasonCutoffsOptions creates a SeasonCutoffsOptions from the query params, along with the optional score to work
+// out the distances to the cutoffs from.
+func seasonCutoffsOptions(q url.Values) (*rio.SeasonCutoffsOptions, *float64, error) {
+	season := strings.ToLower(strings.TrimSpace(q.Get("season")))
+	if season == "" {
+		return nil, nil, errors.New("query param 'season' is required, e.g. 'season-tww-1'")
+	}
+
+	region := strings.ToLower(q.Get("region"))
+	if !slices.Contains(middleware.Regions, region) {
+		return nil, nil, fmt.Errorf("query param 'region' must be one of %s", strings.Join(middleware.Regions, ", "))
+	}
+
+	var score *float64
+	if q.Has("score") {
+		s, err := strconv.ParseFloat(q.Get("score"), 64)
+		if err != nil || s < 0 {
+			return nil, nil, errors.New("optional query param 'score' must be a positive number")
+		}
+		score = &s
+	}
+
+	return &rio.SeasonCutoffsOptions{Season: season, Region: region}, score, nil
+}
+
+// raidDifficulty validates the required raid & difficulty query params shared by the raiding endpoints.
+func raidDifficulty(q url.Values) (string, string, error) {
+	raid := strings.ToLower(strings.TrimSpace(q.Get("raid")))
+	if raid == "" {
+		return "", "", errors.New("query param 'raid' is required, e.g. 'nerubar-palace'")
+	}
+
+	difficulty := strings.ToLower(q.Get("difficulty"))
+	if !slices.Contains(rio.Difficulties, difficulty) {
+		return "", "", fmt.Errorf("query param 'difficulty' must be one of %s", strings.Join(rio.Difficulties, ", "))
+	}
+
+	return raid, difficulty, nil
+}
+
+// guildBossKillOptions sets the raid, boss & difficulty of the options from the query params.
+func guildBossKillOptions(q url.Values, options *rio.GuildBossKillOptions) error {
+	raid, difficulty, err := raidDifficulty(q)
+	if err != nil {
+		return err
+	}
+
+	boss := strings.ToLower(strings.TrimSpace(q.Get("boss")))
+	if boss == "" {
+		return errors.New("query param 'boss' is required, e.g. 'ulgrax-the-devourer'")
+	}
+
+	options.Raid = raid
+	options.Boss = boss
+	options.Difficulty = difficulty
+
+	return nil
+}
+
+// raidingProgressionOptions creates a RaidingProgressionOptions from the query params, the region defaulting to world.
+func raidingProgressionOptions(q url.Values) (*rio.RaidingProgressionOptions, error) {
+	raid, difficulty, err := raidDifficulty(q)
+	if err != nil {
+		return nil, err
+	}
+
+	region := "world"
+	if q.Has("region") {
+		region = strings.ToLower(q.Get("region"))
+		if region != "world" && !slices.Contains(middleware.Regions, region) {
+			return nil, fmt.Errorf("optional query param 'region' must be world or one of %s", strings.Join(middleware.Regions, ", "))
+		}
+	}
+
+	return &rio.RaidingProgressionOptions{
+		Raid:       raid,
+		Difficulty: difficulty,
+		Region:     region,
+	}, nil
+}
+
 func (i *RaiderIO) Route(r *mux.Router) {
+	r.HandleFunc("/raiderio/raiding/progression", i.RaidingProgression).Methods(http.MethodGet)
+	r.HandleFunc("/raiderio/mythic-plus/affixes", i.MythicPlusAffixes).Methods(http.MethodGet)
+	r.HandleFunc("/raiderio/mythic-plus/static-data", i.MythicPlusStaticData).Methods(http.MethodGet)
+	r.HandleFunc("/raiderio/mythic-plus/season-cutoffs", i.MythicPlusSeasonCutoffs).Methods(http.MethodGet)
+	r.HandleFunc("/raiderio/mythic-plus/runs", i.MythicPlusRuns).Methods(http.MethodGet)
+	r.HandleFunc("/raiderio/mythic-plus/runs/{season}/{id:[0-9]+}", i.MythicPlusRunDetails).Methods(http.MethodGet)
+
+	guildRouter := r.PathPrefix("/raiderio/guild/{region}/{realm}/{guild}").Subrouter()
+	guildRouter.Use(middleware.UseRegion().Middleware)
+	guildRouter.Use(middleware.UseRealm().Middleware)
+	guildRouter.Use(middleware.UseGuild().Middleware)
+
+	guildRouter.HandleFunc("", i.GuildProfile).Methods(http.MethodGet)
+	guildRouter.HandleFunc("/boss-kill", i.GuildBossKill).Methods(http.MethodGet)
+
 	rioRouter := r.PathPrefix("/raiderio/{region}/{realm}/{character}").Subrouter()
 	rioRouter.Use(middleware.UseRegion().Middleware)
 	rioRouter.Use(middleware.UseRealm().Middleware)
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestGuildBossKillOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *rio.GuildBossKillOptions
+		wantErr bool
+	}{
+		{
+			name:  "boss kill",
+			query: "raid=nerubar-palace&boss=Ulgrax-The-Devourer&difficulty=Mythic",
+			want: &rio.GuildBossKillOptions{
+				Guild:      "heckin",
+				Raid:       "nerubar-palace",
+				Boss:       "ulgrax-the-devourer",
+				Difficulty: "mythic",
+			},
+		},
+		{name: "missing raid", query: "boss=ulgrax-the-devourer&difficulty=mythic", wantErr: true},
+		{name: "missing boss", query: "raid=nerubar-palace&difficulty=mythic", wantErr: true},
+		{name: "unknown difficulty", query: "raid=nerubar-palace&boss=ulgrax-the-devourer&difficulty=lfr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := &rio.GuildBossKillOptions{Guild: "heckin"}
+			err = guildBossKillOptions(q, options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, options)
+		})
+	}
+}
+
+func TestRaidingProgressionOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *rio.RaidingProgressionOptions
+		wantErr bool
+	}{
+		{
+			name:  "defaults to world",
+			query: "raid=nerubar-palace&difficulty=heroic",
+			want:  &rio.RaidingProgressionOptions{Raid: "nerubar-palace", Difficulty: "heroic", Region: "world"},
+		},
+		{
+			name:  "region",
+			query: "raid=nerubar-palace&difficulty=mythic&region=EU",
+			want:  &rio.RaidingProgressionOptions{Raid: "nerubar-palace", Difficulty: "mythic", Region: "eu"},
+		},
+		{name: "unknown region", query: "raid=nerubar-palace&difficulty=mythic&region=cn", wantErr: true},
+		{name: "missing difficulty", query: "raid=nerubar-palace", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := raidingProgressionOptions(q)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAffixesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *rio.AffixesOptions
+		wantErr bool
+	}{
+		{name: "defaults", query: "", want: &rio.AffixesOptions{Region: "us", Locale: "en"}},
+		{name: "region & locale", query: "region=EU&locale=DE", want: &rio.AffixesOptions{Region: "eu", Locale: "de"}},
+		{name: "unknown region", query: "region=cn", wantErr: true},
+		{name: "invalid locale", query: "locale=en_US", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := affixesOptions(q)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSeasonCutoffsOptions(t *testing.T) {
+	score := 2750.5
+
+	tests := []struct {
+		name      string
+		query     string
+		want      *rio.SeasonCutoffsOptions
+		wantScore *float64
+		wantErr   bool
+	}{
+		{
+			name:  "cutoffs",
+			query: "season=season-tww-1&region=us",
+			want:  &rio.SeasonCutoffsOptions{Season: "season-tww-1", Region: "us"},
+		},
+		{
+			name:      "with score",
+			query:     "season=season-tww-1&region=eu&score=2750.5",
+			want:      &rio.SeasonCutoffsOptions{Season: "season-tww-1", Region: "eu"},
+			wantScore: &score,
+		},
+		{name: "missing season", query: "region=us", wantErr: true},
+		{name: "missing region", query: "season=season-tww-1", wantErr: true},
+		{name: "negative score", query: "season=season-tww-1&region=us&score=-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, gotScore, err := seasonCutoffsOptions(q)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantScore, gotScore)
+		})
+	}
+}
+
+func TestRunsOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *rio.RunsOptions
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			query: "season=season-tww-1",
+			want:  &rio.RunsOptions{Season: "season-tww-1", Region: "world", Dungeon: "all", Affixes: "all"},
+		},
+		{
+			name:  "filters",
+			query: "season=season-tww-1&region=US&dungeon=the-stonevault&affixes=current&page=2",
+			want:  &rio.RunsOptions{Season: "season-tww-1", Region: "us", Dungeon: "the-stonevault", Affixes: "current", Page: 2},
+		},
+		{name: "missing season", query: "region=us", wantErr: true},
+		{name: "unknown region", query: "season=season-tww-1&region=cn", wantErr: true},
+		{name: "negative page", query: "season=season-tww-1&page=-1", wantErr: true},
+		{name: "empty dungeon", query: "season=season-tww-1&dungeon=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := runsOptions(q)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/heckin-dev/amashan/pkg/calendar"
+	"github.com/heckin-dev/amashan/pkg/middleware"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxVaultRosterCharacters is the most characters VaultRoster tracks per request, a full raid & some.
+const maxVaultRosterCharacters = 40
+
+// GreatVault serves the Great Vault progress of characters for their region's current week.
+type GreatVault struct {
+	l hclog.Logger
+
+	tracker *analysis.VaultTracker
+}
+
+func (g *GreatVault) Vault(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	key := r.URL.Path
+
+	options := &analysis.VaultOptions{
+		Region:    r.Context().Value(middleware.RegionContextKey).(string),
+		Realm:     r.Context().Value(middleware.RealmContextKey).(string),
+		Character: r.Context().Value(middleware.CharacterContextKey).(string),
+	}
+
+	// Never cache the vault past the reset.
+	nextResetAt, _ := calendar.NextWeeklyReset(options.Region, time.Now())
+	duration := min(5*time.Minute, time.Until(nextResetAt))
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	vault, err := g.tracker.Vault(r.Context(), options)
+	if err != nil {
+		g.l.Error("failed to track the great vault", "error", err)
+		http.Error(w, "failed to track the great vault", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the GreatVault
+	bs, err := json.Marshal(vault)
+	if err != nil {
+		g.l.Error("json.Marshal failed for GreatVault", "error", err)
+		http.Error(w, "failed to marshal great vault", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+type vaultRosterRequest struct {
+	Characters []analysis.VaultOptions `json:"characters"`
+}
+
+// VaultRoster tracks the Great Vault of many characters at once, the ones needing the most keys first.
+func (g *GreatVault) VaultRoster(w http.ResponseWriter, r *http.Request) {
+	characters, err := vaultRosterCharacters(http.MaxBytesReader(w, r.Body, 1<<16))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	roster := g.tracker.Roster(r.Context(), characters)
+
+	// Marshal the VaultRoster
+	bs, err := json.Marshal(roster)
+	if err != nil {
+		g.l.Error("json.Marshal failed for VaultRoster", "error", err)
+		http.Error(w, "failed to marshal great vault roster", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// vaultRosterCharacters reads & validates the characters of a VaultRoster request body.
+func vaultRosterCharacters(body io.Reader) ([]analysis.VaultOptions, error) {
+	var req vaultRosterRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, errors.New("request body must be a json object with a list of 'characters'")
+	}
+
+	if len(req.Characters) == 0 || len(req.Characters) > maxVaultRosterCharacters {
+		return nil, fmt.Errorf("request body must have between 1-%d 'characters'", maxVaultRosterCharacters)
+	}
+
+	for i, c := range req.Characters {
+		var err error
+		if c.Region, c.Realm, c.Character, err = middleware.ValidateCharacter(c.Region, c.Realm, c.Character); err != nil {
+			return nil, fmt.Errorf("characters[%d] %w", i, err)
+		}
+
+		req.Characters[i] = c
+	}
+
+	return req.Characters, nil
+}
+
+func (g *GreatVault) Route(r *mux.Router) {
+	r.HandleFunc("/great-vault", g.VaultRoster).Methods(http.MethodPost)
+
+	rrcRouter := r.PathPrefix("/{region}/wow/{realm}/{character}").Subrouter()
+	rrcRouter.Use(middleware.UseRegion().Middleware)
+	rrcRouter.Use(middleware.UseRealm().Middleware)
+	rrcRouter.Use(middleware.UseCharacter().Middleware)
+
+	rrcRouter.HandleFunc("/great-vault", g.Vault).Methods(http.MethodGet)
+}
+
+func NewGreatVault(l hclog.Logger, battleNet *BattleNet, raiderIO *RaiderIO) *GreatVault {
+	return &GreatVault{
+		l:       l,
+		tracker: analysis.NewVaultTracker(l, battleNet.client, raiderIO.client),
+	}
+}
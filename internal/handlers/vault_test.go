@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/heckin-dev/amashan/pkg/analysis"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestVaultRosterCharacters(t *testing.T) {
+	tooMany := strings.Repeat(`{"region":"us","realm":"illidan","name":"skxyz"},`, maxVaultRosterCharacters+1)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    []analysis.VaultOptions
+		wantErr string
+	}{
+		{
+			name: "lower-cased",
+			body: `{"characters":[{"region":"US","realm":"Illidan","name":"Skxyz"}]}`,
+			want: []analysis.VaultOptions{{Region: "us", Realm: "illidan", Character: "skxyz"}},
+		},
+		{name: "not json", body: `characters`, wantErr: "request body must be a json object with a list of 'characters'"},
+		{name: "empty", body: `{"characters":[]}`, wantErr: "request body must have between 1-40 'characters'"},
+		{name: "too many", body: fmt.Sprintf(`{"characters":[%s]}`, strings.TrimSuffix(tooMany, ",")), wantErr: "request body must have between 1-40 'characters'"},
+		{name: "bad region", body: `{"characters":[{"region":"oc","realm":"illidan","name":"skxyz"}]}`, wantErr: "characters[0] region 'oc' is not a supported region"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			characters, err := vaultRosterCharacters(strings.NewReader(tt.body))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, characters)
+		})
+	}
+}
@@ -1,21 +1,47 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/analysis"
 	"github.com/heckin-dev/amashan/pkg/middleware"
 	"github.com/heckin-dev/amashan/pkg/wl"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type WarcraftLogs struct {
 	l hclog.Logger
 
-	client *wl.WarcraftLogsClient
+	client     *wl.WarcraftLogsClient
+	wipes      *analysis.WipeAnalyzer
+	attendance *analysis.AttendanceCalculator
+}
+
+// Ready reports whether the WarcraftLogs client has finished its setup.
+func (wls *WarcraftLogs) Ready() bool {
+	return wls.client.Ready()
+}
+
+// RequireReady responds with 503 until the WarcraftLogs client has finished its setup.
+func (wls *WarcraftLogs) RequireReady(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !wls.client.Ready() {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "warcraftlogs is currently unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
 }
 
 func (wls *WarcraftLogs) ClearCachedExpansion(w http.ResponseWriter, r *http.Request) {
@@ -33,10 +59,36 @@ func (wls *WarcraftLogs) ClearCachedExpansion(w http.ResponseWriter, r *http.Req
 	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
 	go cache.Del("/api/warcraftlogs/partitions")
 
-	wls.client.ClearPartitionedExpansion()
+	wls.client.ClearExpansionCatalog()
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RefreshCatalog refreshes the expansion catalog in the background until the context is done, deleting the cached
+// responses built from it whenever it changes.
+func (wls *WarcraftLogs) RefreshCatalog(ctx context.Context, cache middleware.CacheClient) {
+	refresher := wl.NewCatalogRefresher(wls.l, wls.client)
+	refresher.OnChange(func(diff *wl.CatalogDiff) {
+		cache.Del("/api/warcraftlogs/partitions")
+		cache.Del("/api/warcraftlogs/expansions")
+
+		// Zones may have moved between any of the expansions, removed ones included.
+		ids := map[int]bool{}
+		for _, e := range diff.RemovedExpansions {
+			ids[e.ID] = true
+		}
+		if catalog, err := wls.client.GetExpansionCatalog(ctx); err == nil {
+			for _, e := range catalog.Expansions() {
+				ids[e.ID] = true
+			}
+		}
+		for id := range ids {
+			cache.Del(fmt.Sprintf("/api/warcraftlogs/expansions/%d/zones", id))
+		}
+	})
+
+	go refresher.Run(ctx)
+}
+
 func (wls *WarcraftLogs) Partitions(w http.ResponseWriter, r *http.Request) {
 	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
 	duration := 12 * time.Hour
@@ -73,10 +125,106 @@ func (wls *WarcraftLogs) Partitions(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(bs)
 }
 
+func (wls *WarcraftLogs) Expansions(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 12 * time.Hour
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	catalog, err := wls.client.GetExpansionCatalog(r.Context())
+	if err != nil {
+		wls.l.Error("failed to retrieve expansion catalog", "error", err)
+		http.Error(w, "failed to retrieve expansions", http.StatusInternalServerError)
+		return
+	}
+
+	expansions := []*wl.ExpansionDTO{}
+	for _, e := range catalog.Expansions() {
+		expansions = append(expansions, e.DTO())
+	}
+
+	// Marshal the Expansions
+	bs, err := json.Marshal(expansions)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for Expansions", "error", err)
+		http.Error(w, "failed to marshal expansions", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) ExpansionZones(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 12 * time.Hour
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "route param 'id' must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := wls.client.GetExpansionCatalog(r.Context())
+	if err != nil {
+		wls.l.Error("failed to retrieve expansion catalog", "error", err)
+		http.Error(w, "failed to retrieve expansion zones", http.StatusInternalServerError)
+		return
+	}
+
+	expansion, err := catalog.Expansion(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Marshal the ExpansionZones
+	bs, err := json.Marshal(expansion.Zones)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for ExpansionZones", "error", err)
+		http.Error(w, "failed to marshal expansion zones", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) PointBudget(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(wls.client.GetPointBudget())
+}
+
 func (wls *WarcraftLogs) CharacterParses(w http.ResponseWriter, r *http.Request) {
 	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
 	duration := 5 * time.Minute
-	key := r.RequestURI
+	version := middleware.APIVersionFromContext(r.Context())
+	key := fmt.Sprintf("v%d:%s", version, r.RequestURI)
 
 	// Cache HIT
 	if val, err := cache.Get(r.Context(), key); err == nil {
@@ -102,25 +250,30 @@ func (wls *WarcraftLogs) CharacterParses(w http.ResponseWriter, r *http.Request)
 	}
 	options.ZoneID = zone
 
-	if q.Has("partition") {
-		partitionStr := q.Get("partition")
-		partition, err := strconv.Atoi(partitionStr)
-		if err != nil {
-			http.Error(w, "optional query param 'partition' must be an integer", http.StatusBadRequest)
-			return
-		}
-		options.Partition = &partition
+	if err := characterRankingOptions(q, options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	parses, err := wls.client.GetParsesForCharacter(r.Context(), options)
 	if err != nil {
+		if errors.Is(err, wl.ErrZoneNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
 		wls.l.Error("failed to retrieve character parses", "error", err)
 		http.Error(w, "failed to retrieve character parses", http.StatusInternalServerError)
 		return
 	}
 
-	// Marshal the CharacterParses
-	bs, err := json.Marshal(parses)
+	// Marshal the CharacterParses, version 1 is the raw GraphQL response
+	var body any = parses.ToDTO()
+	if version == 1 {
+		body = parses
+	}
+
+	bs, err := json.Marshal(body)
 	if err != nil {
 		wls.l.Error("json.Marshal failed for CharacterParses", "error", err)
 		http.Error(w, "failed to marshal character parses", http.StatusInternalServerError)
@@ -135,23 +288,865 @@ func (wls *WarcraftLogs) CharacterParses(w http.ResponseWriter, r *http.Request)
 	_, _ = w.Write(bs)
 }
 
-func (wls *WarcraftLogs) Route(r *mux.Router) {
-	wlRouter := r.PathPrefix("/warcraftlogs").Subrouter()
+func (wls *WarcraftLogs) CharacterEncounterRankings(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
 
-	wlRouter.HandleFunc("", wls.ClearCachedExpansion)
-	wlRouter.HandleFunc("/partitions", wls.Partitions)
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
 
-	rrcRouter := wlRouter.PathPrefix("/{region}/{realm}/{character}").Subrouter()
-	rrcRouter.Use(middleware.UseRegion().Middleware)
-	rrcRouter.Use(middleware.UseRealm().Middleware)
-	rrcRouter.Use(middleware.UseCharacter().Middleware)
+	options := wl.CharacterParsesQueryOptionsFromContext(r.Context())
 
-	rrcRouter.HandleFunc("/parses", wls.CharacterParses)
+	encounterID, err := strconv.Atoi(mux.Vars(r)["encounterID"])
+	if err != nil {
+		http.Error(w, "route param 'encounterID' must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := characterRankingOptions(r.URL.Query(), options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rankings, err := wls.client.GetEncounterRankingsForCharacter(r.Context(), options, encounterID)
+	if err != nil {
+		wls.l.Error("failed to retrieve character encounter rankings", "encounter", encounterID, "error", err)
+		http.Error(w, "failed to retrieve character encounter rankings", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the CharacterEncounterRankings
+	bs, err := json.Marshal(rankings.ToDTO())
+	if err != nil {
+		wls.l.Error("json.Marshal failed for CharacterEncounterRankings", "error", err)
+		http.Error(w, "failed to marshal character encounter rankings", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
 }
 
-func NewWarcraftLogs(l hclog.Logger) *WarcraftLogs {
+// maxBulkCharacters is the most characters BulkCharacterParses looks up per request, a full raid & some.
+const maxBulkCharacters = 40
+
+type bulkCharacterParsesRequest struct {
+	Characters []wl.BatchCharacter `json:"characters"`
+}
+
+func (wls *WarcraftLogs) BulkCharacterParses(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !q.Has("zone_id") {
+		http.Error(w, "missing required query param 'zone_id'", http.StatusBadRequest)
+		return
+	}
+
+	zone, err := strconv.Atoi(q.Get("zone_id"))
+	if err != nil {
+		http.Error(w, "query param 'zone_id' must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	options := &wl.CharacterParsesQueryOptions{ZoneID: zone}
+	if err := characterRankingOptions(q, options); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var body bulkCharacterParsesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&body); err != nil {
+		http.Error(w, "request body must be a json object with a list of 'characters'", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Characters) == 0 || len(body.Characters) > maxBulkCharacters {
+		http.Error(w, fmt.Sprintf("request body must have between 1-%d 'characters'", maxBulkCharacters), http.StatusBadRequest)
+		return
+	}
+
+	for i, c := range body.Characters {
+		if c.ServerRegion, c.ServerSlug, c.Name, err = middleware.ValidateCharacter(c.ServerRegion, c.ServerSlug, c.Name); err != nil {
+			http.Error(w, fmt.Sprintf("characters[%d] %s", i, err), http.StatusBadRequest)
+			return
+		}
+
+		body.Characters[i] = c
+	}
+
+	parses, err := wls.client.GetParsesForCharacters(r.Context(), options, body.Characters)
+	if err != nil {
+		if errors.Is(err, wl.ErrZoneNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve bulk character parses", "characters", len(body.Characters), "error", err)
+		http.Error(w, "failed to retrieve character parses", http.StatusInternalServerError)
+		return
+	}
+
+	dto := &wl.BulkCharacterParsesDTO{}
+	for i, p := range parses {
+		parse := p.ToDTO()
+		dto.Characters = append(dto.Characters, &wl.CharacterParseSummaryDTO{
+			BatchCharacter: body.Characters[i],
+			Found:          p.CharacterData.Character != nil,
+			Hidden:         parse.Hidden,
+			Summary:        parse.Summary,
+		})
+	}
+
+	// Marshal the BulkCharacterParses
+	bs, err := json.Marshal(dto)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for BulkCharacterParses", "error", err)
+		http.Error(w, "failed to marshal character parses", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// characterRankingOptions parses the optional query params filtering a character's rankings onto the options.
+func characterRankingOptions(q url.Values, options *wl.CharacterParsesQueryOptions) error {
+	if q.Has("partition") {
+		partition, err := strconv.Atoi(q.Get("partition"))
+		if err != nil {
+			return errors.New("optional query param 'partition' must be an integer")
+		}
+		options.Partition = &partition
+	}
+
+	if q.Has("metric") {
+		metric, ok := wl.RankingMetricsMap[strings.ToLower(q.Get("metric"))]
+		if !ok {
+			metrics := make([]string, 0, len(wl.RankingMetricsMap))
+			for m := range wl.RankingMetricsMap {
+				metrics = append(metrics, m)
+			}
+			slices.Sort(metrics)
+
+			return fmt.Errorf("optional query param 'metric' must be one of %s", strings.Join(metrics, ", "))
+		}
+		options.Metric = &metric
+	}
+
+	if q.Has("difficulty") {
+		difficulty, ok := wl.DifficultiesMap[strings.ToLower(q.Get("difficulty"))]
+		if !ok {
+			var err error
+			if difficulty, err = strconv.Atoi(q.Get("difficulty")); err != nil {
+				return errors.New("optional query param 'difficulty' must be an integer or one of lfr, normal, heroic, mythic")
+			}
+		}
+		options.Difficulty = &difficulty
+	}
+
+	if q.Has("size") {
+		size, err := strconv.Atoi(q.Get("size"))
+		if err != nil || size < 1 || size > 40 {
+			return errors.New("optional query param 'size' must be an integer between 1-40")
+		}
+		options.Size = &size
+	}
+
+	if q.Has("role") {
+		role, ok := wl.RolesMap[strings.ToLower(q.Get("role"))]
+		if !ok {
+			return errors.New("optional query param 'role' must be one of any, dps, healer, tank")
+		}
+		options.Role = &role
+	}
+
+	if q.Has("spec") {
+		if !q.Has("class") {
+			return errors.New("optional query param 'spec' requires the query param 'class'")
+		}
+		spec := q.Get("spec")
+		options.SpecName = &spec
+	}
+
+	if q.Has("class") {
+		class := q.Get("class")
+		options.ClassName = &class
+	}
+
+	if q.Has("timeframe") {
+		timeframe, ok := wl.TimeframesMap[strings.ToLower(q.Get("timeframe"))]
+		if !ok {
+			return errors.New("optional query param 'timeframe' must be one of today, historical")
+		}
+		options.Timeframe = &timeframe
+	}
+
+	return nil
+}
+
+func (wls *WarcraftLogs) EncounterRankings(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 15 * time.Minute
+	key := r.RequestURI
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	encounterID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "route param 'id' must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	rankingType := "characters"
+	if q.Has("type") {
+		rankingType = strings.ToLower(q.Get("type"))
+	}
+	if rankingType != "characters" && rankingType != "fights" {
+		http.Error(w, "optional query param 'type' must be one of characters, fights", http.StatusBadRequest)
+		return
+	}
+
+	options := &wl.WorldRankingsOptions{
+		EncounterID: encounterID,
+		Page:        1,
+	}
+	if err := worldRankingsOptions(q, options, rankingType == "fights"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var rankings any
+	if rankingType == "fights" {
+		var wfq *wl.WorldEncounterFightRankingsQuery
+		if wfq, err = wls.client.GetEncounterFightRankings(r.Context(), options); err == nil {
+			rankings = wfq.ToDTO()
+		}
+	} else {
+		var wcq *wl.WorldEncounterCharacterRankingsQuery
+		if wcq, err = wls.client.GetEncounterCharacterRankings(r.Context(), options); err == nil {
+			rankings = wcq.ToDTO()
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, wl.ErrEncounterNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve encounter rankings", "encounter", encounterID, "type", rankingType, "error", err)
+		http.Error(w, "failed to retrieve encounter rankings", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the EncounterRankings
+	bs, err := json.Marshal(rankings)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for EncounterRankings", "error", err)
+		http.Error(w, "failed to marshal encounter rankings", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// worldRankingsOptions parses the optional query params filtering an encounter's world rankings onto the options. The
+// 'metric' is a wl.FightRankingMetricType for fight rankings, and 'class' & 'spec' only filter character rankings.
+func worldRankingsOptions(q url.Values, options *wl.WorldRankingsOptions, fights bool) error {
+	if q.Has("page") {
+		page, err := strconv.Atoi(q.Get("page"))
+		if err != nil || page < 1 {
+			return errors.New("optional query param 'page' must be a positive integer")
+		}
+		options.Page = page
+	}
+
+	if q.Has("region") {
+		region := strings.ToLower(q.Get("region"))
+		if !slices.Contains(middleware.Regions, region) {
+			return fmt.Errorf("optional query param 'region' must be one of %s", strings.Join(middleware.Regions, ", "))
+		}
+		options.ServerRegion = &region
+	}
+
+	if q.Has("metric") {
+		if fights {
+			metric, ok := wl.FightMetricsMap[strings.ToLower(q.Get("metric"))]
+			if !ok {
+				return errors.New("optional query param 'metric' must be one of default, execution, feats, score, speed, progress")
+			}
+			options.FightMetric = &metric
+		} else {
+			metric, ok := wl.RankingMetricsMap[strings.ToLower(q.Get("metric"))]
+			if !ok {
+				return errors.New("optional query param 'metric' is not a supported character ranking metric")
+			}
+			options.Metric = &metric
+		}
+	}
+
+	if !fights {
+		if q.Has("spec") && !q.Has("class") {
+			return errors.New("optional query param 'spec' requires the query param 'class'")
+		}
+
+		if q.Has("class") {
+			class := q.Get("class")
+			options.ClassName = &class
+		}
+
+		if q.Has("spec") {
+			spec := q.Get("spec")
+			options.SpecName = &spec
+		}
+	}
+
+	if q.Has("difficulty") {
+		difficulty, ok := wl.DifficultiesMap[strings.ToLower(q.Get("difficulty"))]
+		if !ok {
+			var err error
+			if difficulty, err = strconv.Atoi(q.Get("difficulty")); err != nil {
+				return errors.New("optional query param 'difficulty' must be an integer or one of lfr, normal, heroic, mythic")
+			}
+		}
+		options.Difficulty = &difficulty
+	}
+
+	if q.Has("size") {
+		size, err := strconv.Atoi(q.Get("size"))
+		if err != nil || size < 1 || size > 40 {
+			return errors.New("optional query param 'size' must be an integer between 1-40")
+		}
+		options.Size = &size
+	}
+
+	if q.Has("partition") {
+		partition, err := strconv.Atoi(q.Get("partition"))
+		if err != nil {
+			return errors.New("optional query param 'partition' must be an integer")
+		}
+		options.Partition = &partition
+	}
+
+	return nil
+}
+
+func (wls *WarcraftLogs) Report(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	code := mux.Vars(r)["code"]
+
+	report, err := wls.client.GetReport(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, wl.ErrReportNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve report", "code", code, "error", err)
+		http.Error(w, "failed to retrieve report", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the Report
+	bs, err := json.Marshal(report.ToDTO())
+	if err != nil {
+		wls.l.Error("json.Marshal failed for Report", "error", err)
+		http.Error(w, "failed to marshal report", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) ReportFight(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	fightID, err := strconv.Atoi(vars["fightID"])
+	if err != nil {
+		http.Error(w, "failed to parse fightID to integer", http.StatusBadRequest)
+		return
+	}
+
+	fight, err := wls.client.GetReportFight(r.Context(), code, fightID)
+	if err != nil {
+		if errors.Is(err, wl.ErrReportNotFound) || errors.Is(err, wl.ErrFightNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve report fight", "code", code, "fight", fightID, "error", err)
+		http.Error(w, "failed to retrieve report fight", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the ReportFight
+	bs, err := json.Marshal(fight.ToDTO())
+	if err != nil {
+		wls.l.Error("json.Marshal failed for ReportFight", "error", err)
+		http.Error(w, "failed to marshal report fight", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// reportDataOptions reads the ReportDataOptions for a report table or graph from the request.
+func reportDataOptions(r *http.Request) (*wl.ReportDataOptions, error) {
+	vars := mux.Vars(r)
+	options := &wl.ReportDataOptions{
+		Code: vars["code"],
+	}
+
+	dataType, ok := wl.ReportDataTypesMap[vars["dataType"]]
+	if !ok {
+		return nil, fmt.Errorf("data type '%s' is not a supported data type", vars["dataType"])
+	}
+	options.DataType = dataType
+
+	q := r.URL.Query()
+	if !q.Has("fight_ids") {
+		return nil, errors.New("missing required query param 'fight_ids'")
+	}
+
+	for _, idStr := range strings.Split(q.Get("fight_ids"), ",") {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, errors.New("query param 'fight_ids' must be a comma separated list of integers")
+		}
+		options.FightIDs = append(options.FightIDs, id)
+	}
+
+	if q.Has("source_id") {
+		id, err := strconv.Atoi(q.Get("source_id"))
+		if err != nil {
+			return nil, errors.New("optional query param 'source_id' must be an integer")
+		}
+		options.SourceID = &id
+	}
+
+	if q.Has("ability_id") {
+		id, err := strconv.Atoi(q.Get("ability_id"))
+		if err != nil {
+			return nil, errors.New("optional query param 'ability_id' must be an integer")
+		}
+		options.AbilityID = &id
+	}
+
+	return options, nil
+}
+
+func (wls *WarcraftLogs) ReportTable(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	options, err := reportDataOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	table, err := wls.client.GetReportTable(r.Context(), options)
+	if err != nil {
+		if errors.Is(err, wl.ErrReportNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve report table", "code", options.Code, "error", err)
+		http.Error(w, "failed to retrieve report table", http.StatusInternalServerError)
+		return
+	}
+
+	dto := table.ToDTO()
+	dto.DataType = options.DataType
+	dto.FightIDs = options.FightIDs
+
+	// Marshal the ReportTable
+	bs, err := json.Marshal(dto)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for ReportTable", "error", err)
+		http.Error(w, "failed to marshal report table", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) ReportGraph(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	options, err := reportDataOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	graph, err := wls.client.GetReportGraph(r.Context(), options)
+	if err != nil {
+		if errors.Is(err, wl.ErrReportNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		wls.l.Error("failed to retrieve report graph", "code", options.Code, "error", err)
+		http.Error(w, "failed to retrieve report graph", http.StatusInternalServerError)
+		return
+	}
+
+	dto := graph.ToDTO()
+	dto.DataType = options.DataType
+	dto.FightIDs = options.FightIDs
+
+	// Marshal the ReportGraph
+	bs, err := json.Marshal(dto)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for ReportGraph", "error", err)
+		http.Error(w, "failed to marshal report graph", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) ReportWipes(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.URL.Path
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	code := mux.Vars(r)["code"]
+
+	wipes, err := wls.wipes.Analyze(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, wl.ErrReportNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, wl.ErrTooManyEvents) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		wls.l.Error("failed to analyze report wipes", "code", code, "error", err)
+		http.Error(w, "failed to analyze report wipes", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the WipeAnalysisDTO
+	bs, err := json.Marshal(wipes)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for WipeAnalysisDTO", "error", err)
+		http.Error(w, "failed to marshal report wipes", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+func (wls *WarcraftLogs) GuildReports(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 5 * time.Minute
+	key := r.RequestURI
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	options := wl.GuildReportsOptionsFromContext(r.Context())
+
+	q := r.URL.Query()
+	if q.Has("page") {
+		page, err := strconv.Atoi(q.Get("page"))
+		if err != nil || page < 1 {
+			http.Error(w, "optional query param 'page' must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		options.Page = page
+	}
+
+	if q.Has("limit") {
+		limit, err := strconv.Atoi(q.Get("limit"))
+		if err != nil || limit < 1 || limit > wl.DefaultGuildReportsLimit {
+			http.Error(w, fmt.Sprintf("optional query param 'limit' must be an integer between 1-%d", wl.DefaultGuildReportsLimit), http.StatusBadRequest)
+			return
+		}
+		options.Limit = limit
+	}
+
+	if err := guildReportsFilters(q, options, time.UTC); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reports, err := wls.client.GetGuildReports(r.Context(), options)
+	if err != nil {
+		wls.l.Error("failed to retrieve guild reports", "guild", options.GuildName, "error", err)
+		http.Error(w, "failed to retrieve guild reports", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the GuildReports
+	bs, err := json.Marshal(reports.ToDTO())
+	if err != nil {
+		wls.l.Error("json.Marshal failed for GuildReports", "error", err)
+		http.Error(w, "failed to marshal guild reports", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// defaultAttendanceWindow is how far back attendance is calculated without a 'start' query param.
+const defaultAttendanceWindow = 28 * 24 * time.Hour
+
+func (wls *WarcraftLogs) GuildAttendance(w http.ResponseWriter, r *http.Request) {
+	cache := r.Context().Value(middleware.CacheContextKey).(middleware.CacheClient)
+	duration := 30 * time.Minute
+	key := r.RequestURI
+
+	// Cache HIT
+	if val, err := cache.Get(r.Context(), key); err == nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(val))
+		return
+	}
+
+	options := wl.GuildReportsOptionsFromContext(r.Context())
+
+	q := r.URL.Query()
+	loc := time.UTC
+	if q.Has("tz") {
+		l, err := time.LoadLocation(q.Get("tz"))
+		if err != nil {
+			http.Error(w, "optional query param 'tz' must be an IANA time zone, e.g. America/New_York", http.StatusBadRequest)
+			return
+		}
+		loc = l
+	}
+
+	if err := guildReportsFilters(q, options, loc); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if options.EndTime == nil {
+		end := time.Now().In(loc)
+		options.EndTime = &end
+	}
+	if options.StartTime == nil {
+		start := options.EndTime.Add(-defaultAttendanceWindow)
+		options.StartTime = &start
+	}
+
+	if !options.StartTime.Before(*options.EndTime) {
+		http.Error(w, "query param 'start' must be before 'end'", http.StatusBadRequest)
+		return
+	}
+
+	attendance, err := wls.attendance.Calculate(r.Context(), options, loc)
+	if err != nil {
+		wls.l.Error("failed to calculate guild attendance", "guild", options.GuildName, "error", err)
+		http.Error(w, "failed to calculate guild attendance", http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the AttendanceDTO
+	bs, err := json.Marshal(attendance)
+	if err != nil {
+		wls.l.Error("json.Marshal failed for AttendanceDTO", "error", err)
+		http.Error(w, "failed to marshal guild attendance", http.StatusInternalServerError)
+		return
+	}
+
+	// Cache SET
+	go cache.Set(key, string(bs), duration)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%.0f", duration.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bs)
+}
+
+// guildReportsFilters parses the optional 'zone_id', 'start' & 'end' query params onto the options. The dates are
+// YYYY-MM-DD in loc, with 'end' including the whole day.
+func guildReportsFilters(q url.Values, options *wl.GuildReportsOptions, loc *time.Location) error {
+	if q.Has("zone_id") {
+		zone, err := strconv.Atoi(q.Get("zone_id"))
+		if err != nil {
+			return errors.New("optional query param 'zone_id' must be an integer")
+		}
+		options.ZoneID = &zone
+	}
+
+	if q.Has("start") {
+		start, err := time.ParseInLocation(time.DateOnly, q.Get("start"), loc)
+		if err != nil {
+			return errors.New("optional query param 'start' must be a date formatted YYYY-MM-DD")
+		}
+		options.StartTime = &start
+	}
+
+	if q.Has("end") {
+		end, err := time.ParseInLocation(time.DateOnly, q.Get("end"), loc)
+		if err != nil {
+			return errors.New("optional query param 'end' must be a date formatted YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		options.EndTime = &end
+	}
+
+	return nil
+}
+
+func (wls *WarcraftLogs) Route(r *mux.Router) {
+	wlRouter := r.PathPrefix("/warcraftlogs").Subrouter()
+	wlRouter.Use(wls.RequireReady)
+
+	wlRouter.HandleFunc("", wls.ClearCachedExpansion)
+	wlRouter.HandleFunc("/partitions", wls.Partitions)
+	wlRouter.HandleFunc("/expansions", wls.Expansions).Methods(http.MethodGet)
+	wlRouter.HandleFunc("/expansions/{id:[0-9]+}/zones", wls.ExpansionZones).Methods(http.MethodGet)
+	wlRouter.HandleFunc("/budget", wls.PointBudget).Methods(http.MethodGet)
+
+	wlRouter.HandleFunc("/characters/parses", wls.BulkCharacterParses).Methods(http.MethodPost)
+	wlRouter.HandleFunc("/encounters/{id:[0-9]+}/rankings", wls.EncounterRankings).Methods(http.MethodGet)
+
+	reportRouter := wlRouter.PathPrefix("/reports/{code:[a-zA-Z0-9]+}").Subrouter()
+	reportRouter.HandleFunc("", wls.Report).Methods(http.MethodGet)
+	reportRouter.HandleFunc("/fights/{fightID:[0-9]+}", wls.ReportFight).Methods(http.MethodGet)
+	reportRouter.HandleFunc("/tables/{dataType}", wls.ReportTable).Methods(http.MethodGet)
+	reportRouter.HandleFunc("/graphs/{dataType}", wls.ReportGraph).Methods(http.MethodGet)
+	reportRouter.HandleFunc("/wipes", wls.ReportWipes).Methods(http.MethodGet)
+
+	guildRouter := wlRouter.PathPrefix("/guilds/{region}/{realm}/{guild}").Subrouter()
+	guildRouter.Use(middleware.UseRegion().Middleware)
+	guildRouter.Use(middleware.UseRealm().Middleware)
+	guildRouter.Use(middleware.UseGuild().Middleware)
+
+	guildRouter.HandleFunc("/reports", wls.GuildReports).Methods(http.MethodGet)
+	guildRouter.HandleFunc("/attendance", wls.GuildAttendance).Methods(http.MethodGet)
+
+	rrcRouter := wlRouter.PathPrefix("/{region}/{realm}/{character}").Subrouter()
+	rrcRouter.Use(middleware.UseRegion().Middleware)
+	rrcRouter.Use(middleware.UseRealm().Middleware)
+	rrcRouter.Use(middleware.UseCharacter().Middleware)
+
+	rrcRouter.HandleFunc("/parses", wls.CharacterParses)
+	rrcRouter.HandleFunc("/encounters/{encounterID:[0-9]+}/rankings", wls.CharacterEncounterRankings).Methods(http.MethodGet)
+}
+
+func NewWarcraftLogs(l hclog.Logger) *WarcraftLogs {
+	client := wl.NewWarcraftLogsClient(l)
+
 	return &WarcraftLogs{
-		l:      l,
-		client: wl.NewWarcraftLogsClient(l),
+		l:          l,
+		client:     client,
+		wipes:      analysis.NewWipeAnalyzer(l, client),
+		attendance: analysis.NewAttendanceCalculator(l, client),
 	}
 }
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWarcraftLogs_RequireReady(t *testing.T) {
+	// Without credentials the client never becomes ready.
+	t.Setenv("WL_CLIENT_ID", "")
+	t.Setenv("WL_CLIENT_SECRET", "")
+
+	wls := NewWarcraftLogs(hclog.Default())
+
+	sm := mux.NewRouter()
+	wls.Route(sm)
+
+	req, err := http.NewRequest(http.MethodGet, "/warcraftlogs/partitions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	sm.ServeHTTP(rr, req)
+
+	assert.Truef(t, rr.Code == http.StatusServiceUnavailable, "got status %v, wanted %v", rr.Code, http.StatusServiceUnavailable)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.False(t, wls.Ready())
+}
+
+func TestCharacterRankingOptions(t *testing.T) {
+	metric, role, timeframe := wl.MetricBossDPS, wl.RoleDPS, wl.TimeframeHistorical
+	class, spec := "Mage", "Frost"
+
+	tests := []struct {
+		name    string
+		query   string
+		want    *wl.CharacterParsesQueryOptions
+		wantErr bool
+	}{
+		{
+			name:  "no filters",
+			query: "",
+			want:  &wl.CharacterParsesQueryOptions{},
+		},
+		{
+			name:  "every filter",
+			query: "partition=2&metric=BossDPS&difficulty=mythic&size=20&role=dps&class=Mage&spec=Frost&timeframe=historical",
+			want: &wl.CharacterParsesQueryOptions{
+				Partition:  wl.Int(2),
+				Metric:     &metric,
+				Difficulty: wl.Int(5),
+				Size:       wl.Int(20),
+				Role:       &role,
+				ClassName:  &class,
+				SpecName:   &spec,
+				Timeframe:  &timeframe,
+			},
+		},
+		{
+			name:  "difficulty by id",
+			query: "difficulty=4",
+			want:  &wl.CharacterParsesQueryOptions{Difficulty: wl.Int(4)},
+		},
+		{name: "unknown metric", query: "metric=fastest", wantErr: true},
+		{name: "unknown difficulty", query: "difficulty=impossible", wantErr: true},
+		{name: "size out of range", query: "size=41", wantErr: true},
+		{name: "unknown role", query: "role=bard", wantErr: true},
+		{name: "spec without class", query: "spec=Frost", wantErr: true},
+		{name: "unknown timeframe", query: "timeframe=tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := &wl.CharacterParsesQueryOptions{}
+			err = characterRankingOptions(q, options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, options)
+		})
+	}
+}
+
+func TestWorldRankingsOptions(t *testing.T) {
+	metric, fightMetric := wl.MetricDPS, wl.FightMetricSpeed
+	region, class, spec := "eu", "Mage", "Frost"
+
+	tests := []struct {
+		name    string
+		query   string
+		fights  bool
+		want    *wl.WorldRankingsOptions
+		wantErr bool
+	}{
+		{
+			name:  "character filters",
+			query: "page=3&region=EU&metric=dps&class=Mage&spec=Frost&difficulty=heroic",
+			want: &wl.WorldRankingsOptions{
+				Page:         3,
+				ServerRegion: &region,
+				Metric:       &metric,
+				ClassName:    &class,
+				SpecName:     &spec,
+				Difficulty:   wl.Int(4),
+			},
+		},
+		{
+			name:   "fight filters ignore class & spec",
+			query:  "metric=speed&class=Mage&size=20",
+			fights: true,
+			want: &wl.WorldRankingsOptions{
+				Page:        1,
+				FightMetric: &fightMetric,
+				Size:        wl.Int(20),
+			},
+		},
+		{name: "character metric on fights", query: "metric=dps", fights: true, wantErr: true},
+		{name: "fight metric on characters", query: "metric=speed", wantErr: true},
+		{name: "unknown region", query: "region=cn", wantErr: true},
+		{name: "page zero", query: "page=0", wantErr: true},
+		{name: "spec without class", query: "spec=Frost", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := &wl.WorldRankingsOptions{Page: 1}
+			err = worldRankingsOptions(q, options, tt.fights)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, options)
+		})
+	}
+}
+
+func TestWarcraftLogs_BulkCharacterParses_Validation(t *testing.T) {
+	t.Setenv("WL_CLIENT_ID", "")
+	t.Setenv("WL_CLIENT_SECRET", "")
+
+	wls := NewWarcraftLogs(hclog.NewNullLogger())
+
+	tests := []struct {
+		name  string
+		query string
+		body  string
+	}{
+		{name: "missing zone", query: "", body: `{"characters": [{"region": "us", "realm": "illidan", "name": "skxyz"}]}`},
+		{name: "bad metric", query: "zone_id=38&metric=fastest", body: `{"characters": [{"region": "us", "realm": "illidan", "name": "skxyz"}]}`},
+		{name: "not json", query: "zone_id=38", body: `us/illidan/skxyz`},
+		{name: "no characters", query: "zone_id=38", body: `{"characters": []}`},
+		{name: "bad region", query: "zone_id=38", body: `{"characters": [{"region": "cn", "realm": "illidan", "name": "skxyz"}]}`},
+		{name: "missing realm", query: "zone_id=38", body: `{"characters": [{"region": "us", "name": "skxyz"}]}`},
+		{name: "long name", query: "zone_id=38", body: `{"characters": [{"region": "us", "realm": "illidan", "name": "skxyzskxyzskxyz"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/warcraftlogs/characters/parses?"+tt.query, strings.NewReader(tt.body))
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(wls.BulkCharacterParses).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
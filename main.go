@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
@@ -9,15 +10,18 @@ import (
 	"github.com/heckin-dev/amashan/pkg/utils"
 	"github.com/joho/godotenv"
 	"os"
+	"time"
 )
 
 var bindAddress string
 var useDotEnv bool
+var rateLimit int
 
 func init() {
 	const (
 		usageBindAddress = "the address to bind to, e.g. :9090"
 		usageUseDotEnv   = "read variables from a .env file in running directory"
+		usageRateLimit   = "the requests per minute allowed for each client"
 	)
 
 	flag.StringVar(&bindAddress, "bindAddress", ":9090", usageBindAddress)
@@ -25,6 +29,9 @@ func init() {
 
 	flag.BoolVar(&useDotEnv, "dotenv", false, usageUseDotEnv)
 	flag.BoolVar(&useDotEnv, "denv", false, usageUseDotEnv)
+
+	flag.IntVar(&rateLimit, "rateLimit", 120, usageRateLimit)
+	flag.IntVar(&rateLimit, "rl", 120, usageRateLimit)
 }
 
 func main() {
@@ -46,13 +53,32 @@ func main() {
 
 	// /api grouping
 	apiRouter := sm.PathPrefix("/api").Subrouter()
-	apiRouter.Use(middleware.UseCaching(l).Middleware)
+	apiRouter.Use(middleware.UseRateLimiting(l, middleware.NewRedisRateLimitStore(l), rateLimit, time.Minute).Middleware)
+	cache := middleware.UseCaching(l)
+	apiRouter.Use(cache.Middleware)
+	apiRouter.Use(middleware.UseVersioning().Middleware)
+
+	warcraftLogs := handlers.NewWarcraftLogs(l)
+	warcraftLogs.RefreshCatalog(context.Background(), cache)
+
+	healthcheck := handlers.NewHealthcheck()
+	healthcheck.Register("warcraftlogs", warcraftLogs)
+
+	battleNet := handlers.NewBattleNet(l)
+	raiderIO := handlers.NewRaiderIO(l)
 
 	// Routes
-	handlers.NewHealthcheck().Route(apiRouter)
-	handlers.NewBattleNet(l).Route(apiRouter)
-	handlers.NewWarcraftLogs(l).Route(apiRouter)
-	handlers.NewRaiderIO(l).Route(apiRouter)
+	healthcheck.Route(apiRouter)
+	battleNet.Route(apiRouter)
+	warcraftLogs.Route(apiRouter)
+	raiderIO.Route(apiRouter)
+	handlers.NewMythicPlus(l, battleNet, raiderIO).Route(apiRouter)
+	greatVault := handlers.NewGreatVault(l, battleNet, raiderIO)
+	greatVault.Route(apiRouter)
+	handlers.NewCalendar(l, battleNet, raiderIO).Route(apiRouter)
+	handlers.NewOverview(l, battleNet, raiderIO, warcraftLogs).Route(apiRouter)
+	handlers.NewBatch(l, battleNet, raiderIO, greatVault).Route(apiRouter)
+	handlers.NewCompare(l, battleNet, raiderIO, warcraftLogs).Route(apiRouter)
 
 	utils.StartServerWithGracefulShutdown(sm, bindAddress, l)
 }
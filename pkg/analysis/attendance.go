@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxAttendancePages caps how many pages of reports an attendance calculation follows.
+const maxAttendancePages = 10
+
+// GuildReportSource is the part of the WarcraftLogsClient attendance is calculated from.
+type GuildReportSource interface {
+	GetGuildReports(ctx context.Context, options *wl.GuildReportsOptions) (*wl.GuildReportsQuery, error)
+}
+
+type AttendanceDTO struct {
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"`
+	Nights     []*RaidNightDTO           `json:"nights"`
+	Characters []*CharacterAttendanceDTO `json:"characters"`
+}
+
+type RaidNightDTO struct {
+	Date    string   `json:"date"`
+	Reports []string `json:"reports"`
+	Players int      `json:"players"`
+}
+
+type CharacterAttendanceDTO struct {
+	Name       string   `json:"name"`
+	Server     string   `json:"server,omitempty"`
+	Class      string   `json:"class"`
+	Attended   int      `json:"attended"`
+	Percentage float64  `json:"percentage"`
+	Nights     []string `json:"nights"`
+}
+
+// AttendanceCalculator works out which characters appeared on which raid nights from a guild's reports.
+type AttendanceCalculator struct {
+	l hclog.Logger
+
+	source GuildReportSource
+}
+
+// Calculate gets every report of the guild between the options' start & end time, grouping them into raid nights by
+// the date they started on in loc.
+func (a *AttendanceCalculator) Calculate(ctx context.Context, options *wl.GuildReportsOptions, loc *time.Location) (*AttendanceDTO, error) {
+	reports, err := a.reports(ctx, *options)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance := &AttendanceDTO{
+		Nights:     []*RaidNightDTO{},
+		Characters: []*CharacterAttendanceDTO{},
+	}
+	if options.StartTime != nil {
+		attendance.From = options.StartTime.In(loc)
+	}
+	if options.EndTime != nil {
+		attendance.To = options.EndTime.In(loc)
+	}
+
+	// Reports come newest first, attendance reads oldest first.
+	slices.SortStableFunc(reports, func(a, b wl.ReportInfo) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+
+	nights := map[string]*RaidNightDTO{}
+	attended := map[string]map[string]bool{}
+	characters := map[string]*CharacterAttendanceDTO{}
+
+	for _, r := range reports {
+		date := time.UnixMilli(int64(r.StartTime)).In(loc).Format(time.DateOnly)
+
+		night, ok := nights[date]
+		if !ok {
+			night = &RaidNightDTO{Date: date}
+			nights[date] = night
+			attended[date] = map[string]bool{}
+			attendance.Nights = append(attendance.Nights, night)
+		}
+		night.Reports = append(night.Reports, string(r.Code))
+
+		for _, p := range r.Players() {
+			key := strings.ToLower(p.Name + "-" + p.Server)
+			if attended[date][key] {
+				continue
+			}
+			attended[date][key] = true
+			night.Players++
+
+			c, ok := characters[key]
+			if !ok {
+				c = &CharacterAttendanceDTO{
+					Name:   p.Name,
+					Server: p.Server,
+					Class:  p.Class,
+				}
+				characters[key] = c
+				attendance.Characters = append(attendance.Characters, c)
+			}
+			c.Attended++
+			c.Nights = append(c.Nights, date)
+		}
+	}
+
+	for _, c := range attendance.Characters {
+		c.Percentage = float64(c.Attended) / float64(len(attendance.Nights)) * 100
+	}
+
+	slices.SortStableFunc(attendance.Characters, func(a, b *CharacterAttendanceDTO) int {
+		if c := cmp.Compare(b.Attended, a.Attended); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return attendance, nil
+}
+
+// reports follows the pages of the guild's reports until there are none left, or maxAttendancePages is reached.
+func (a *AttendanceCalculator) reports(ctx context.Context, options wl.GuildReportsOptions) ([]wl.ReportInfo, error) {
+	var reports []wl.ReportInfo
+	for options.Page = 1; options.Page <= maxAttendancePages; options.Page++ {
+		grq, err := a.source.GetGuildReports(ctx, &options)
+		if err != nil {
+			return nil, err
+		}
+
+		page := grq.ReportData.Reports
+		if page == nil {
+			break
+		}
+		reports = append(reports, page.Data...)
+
+		if !page.HasMorePages {
+			break
+		}
+
+		if options.Page == maxAttendancePages {
+			a.l.Warn("AttendanceCalculator reached the page limit", "guild", options.GuildName, "pages", maxAttendancePages)
+		}
+	}
+
+	return reports, nil
+}
+
+// NewAttendanceCalculator creates an AttendanceCalculator getting the guild's reports from the source.
+func NewAttendanceCalculator(l hclog.Logger, source GuildReportSource) *AttendanceCalculator {
+	return &AttendanceCalculator{
+		l:      l,
+		source: source,
+	}
+}
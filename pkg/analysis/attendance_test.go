@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hasura/go-graphql-client"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeGuildReportSource struct {
+	pages [][]wl.ReportInfo
+	calls int
+}
+
+func (f *fakeGuildReportSource) GetGuildReports(_ context.Context, options *wl.GuildReportsOptions) (*wl.GuildReportsQuery, error) {
+	f.calls++
+
+	return &wl.GuildReportsQuery{
+		ReportData: wl.ReportsData{
+			Reports: &wl.ReportPagination{
+				Data:         f.pages[options.Page-1],
+				CurrentPage:  graphql.Int(options.Page),
+				LastPage:     graphql.Int(len(f.pages)),
+				HasMorePages: graphql.Boolean(options.Page < len(f.pages)),
+			},
+		},
+	}, nil
+}
+
+func raidReport(code string, start time.Time, players ...string) wl.ReportInfo {
+	r := wl.ReportInfo{
+		Code:       graphql.String(code),
+		StartTime:  graphql.Float(start.UnixMilli()),
+		EndTime:    graphql.Float(start.Add(3 * time.Hour).UnixMilli()),
+		MasterData: &wl.ReportMasterData{},
+	}
+
+	server := graphql.String("Illidan")
+	for i, name := range players {
+		r.MasterData.Actors = append(r.MasterData.Actors, wl.ReportActor{
+			ID:      graphql.Int(i + 1),
+			Name:    graphql.String(name),
+			Server:  &server,
+			SubType: "Mage",
+		})
+	}
+
+	return r
+}
+
+func TestAttendanceCalculator_Calculate(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tuesday := time.Date(2024, 9, 17, 20, 0, 0, 0, ny)
+	thursday := time.Date(2024, 9, 19, 20, 0, 0, 0, ny)
+
+	source := &fakeGuildReportSource{
+		pages: [][]wl.ReportInfo{
+			{
+				raidReport("thu2", thursday.Add(2*time.Hour), "Aradin", "Skxyz"),
+				raidReport("thu1", thursday, "Aradin"),
+			},
+			{
+				raidReport("tue1", tuesday, "Aradin", "Bolvar"),
+			},
+		},
+	}
+
+	c := NewAttendanceCalculator(hclog.NewNullLogger(), source)
+	attendance, err := c.Calculate(context.Background(), &wl.GuildReportsOptions{GuildName: "Heckin", Limit: 2}, ny)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	// Thursday's second report starts past midnight UTC, but is the same raid night in New York.
+	assert.Equal(t, []*RaidNightDTO{
+		{Date: "2024-09-17", Reports: []string{"tue1"}, Players: 2},
+		{Date: "2024-09-19", Reports: []string{"thu1", "thu2"}, Players: 2},
+	}, attendance.Nights)
+
+	assert.Len(t, attendance.Characters, 3)
+	assert.Equal(t, "Aradin", attendance.Characters[0].Name)
+	assert.Equal(t, 2, attendance.Characters[0].Attended)
+	assert.Equal(t, float64(100), attendance.Characters[0].Percentage)
+	assert.Equal(t, []string{"2024-09-17", "2024-09-19"}, attendance.Characters[0].Nights)
+
+	assert.Equal(t, "Bolvar", attendance.Characters[1].Name)
+	assert.Equal(t, float64(50), attendance.Characters[1].Percentage)
+	assert.Equal(t, "Skxyz", attendance.Characters[2].Name)
+	assert.Equal(t, []string{"2024-09-19"}, attendance.Characters[2].Nights)
+}
+
+func TestAttendanceCalculator_Calculate_NoReports(t *testing.T) {
+	source := &fakeGuildReportSource{pages: [][]wl.ReportInfo{{}}}
+
+	attendance, err := NewAttendanceCalculator(hclog.NewNullLogger(), source).Calculate(context.Background(), &wl.GuildReportsOptions{}, time.UTC)
+	assert.NoError(t, err)
+	assert.Empty(t, attendance.Nights)
+	assert.Empty(t, attendance.Characters)
+}
@@ -0,0 +1,261 @@
+package analysis
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"slices"
+	"sync"
+)
+
+// MaxComparedCharacters is the most characters compared side by side.
+const MaxComparedCharacters = 5
+
+// averagedSlots are the slots an average item level is over, a two-hander counting for the off hand too.
+const averagedSlots = 16
+
+// comparedSections are the sections of a CharacterOverviewDTO a comparison is made from.
+var comparedSections = []string{SectionEquipment, SectionStatistics, SectionRaiderIO, SectionParses}
+
+// cosmeticSlots are the slots that don't count towards the item level.
+var cosmeticSlots = []string{"SHIRT", "TABARD"}
+
+// The secondary stats compared.
+const (
+	StatCrit        = "crit"
+	StatHaste       = "haste"
+	StatMastery     = "mastery"
+	StatVersatility = "versatility"
+)
+
+// The parse averages compared.
+const (
+	ParseBestAverage   = "best_average"
+	ParseMedianAverage = "median_average"
+)
+
+// CompareOptions are the characters to compare, the first being the baseline, & the Warcraft Logs zone of their parses.
+type CompareOptions struct {
+	Characters []CompareCharacter
+	ZoneID     *int
+}
+
+type CompareCharacter struct {
+	Region    string `json:"region"`
+	Realm     string `json:"realm"`
+	Character string `json:"name"`
+}
+
+type ComparisonDTO struct {
+	// Characters are compared in this order, each delta being against the first character.
+	Characters []*ComparedCharacterDTO `json:"characters"`
+	// SecondaryStats are keyed by the stat, with the stat's percentage as the value.
+	SecondaryStats map[string][]*ComparedValueDTO `json:"secondary_stats"`
+	// ItemLevels are keyed by the slot, with the average item level under "average".
+	ItemLevels      map[string][]*ComparedValueDTO `json:"item_levels"`
+	MythicPlusScore []*ComparedValueDTO            `json:"mythic_plus_score"`
+	// Parses are keyed by the parse average.
+	Parses map[string][]*ComparedValueDTO `json:"parses"`
+}
+
+// Partial reports whether a section of any character couldn't be fetched.
+func (c *ComparisonDTO) Partial() bool {
+	for _, character := range c.Characters {
+		if (&CharacterOverviewDTO{Sources: character.Sources}).Partial() {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ComparedCharacterDTO struct {
+	CompareCharacter
+	// Sources are the status of each section the character's values come from.
+	Sources map[string]*SourceStatusDTO `json:"sources"`
+}
+
+type ComparedValueDTO struct {
+	// Value is nil when the character's section couldn't be fetched, or they don't have it.
+	Value *float64 `json:"value"`
+	// Rating is the rating behind a secondary stat's percentage.
+	Rating *float64 `json:"rating,omitempty"`
+	// Delta is the difference to the first character's value, nil when either is.
+	Delta *float64 `json:"delta"`
+}
+
+// CharacterComparer compares characters' secondary stats, item levels, Mythic+ scores & parses side by side.
+type CharacterComparer struct {
+	l hclog.Logger
+
+	overviews *OverviewAggregator
+}
+
+// Compare fetches the overview of each character at once, comparing them against the first. A character's section
+// failing only leaves their values of it out.
+func (c *CharacterComparer) Compare(ctx context.Context, options *CompareOptions) *ComparisonDTO {
+	overviews := make([]*CharacterOverviewDTO, len(options.Characters))
+
+	var wg sync.WaitGroup
+	for i, character := range options.Characters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			overviews[i] = c.overviews.Overview(ctx, &OverviewOptions{
+				Region:    character.Region,
+				Realm:     character.Realm,
+				Character: character.Character,
+				ZoneID:    options.ZoneID,
+				Sections:  comparedSections,
+			})
+		}()
+	}
+	wg.Wait()
+
+	comparison := &ComparisonDTO{
+		SecondaryStats: map[string][]*ComparedValueDTO{},
+		ItemLevels:     map[string][]*ComparedValueDTO{},
+		Parses:         map[string][]*ComparedValueDTO{},
+	}
+
+	var slots []string
+	for _, o := range overviews {
+		if o.Equipment == nil {
+			continue
+		}
+		for _, item := range o.Equipment.EquippedItems {
+			if !slices.Contains(slots, item.Slot.Type) && !slices.Contains(cosmeticSlots, item.Slot.Type) {
+				slots = append(slots, item.Slot.Type)
+			}
+		}
+	}
+
+	for i, o := range overviews {
+		comparison.Characters = append(comparison.Characters, &ComparedCharacterDTO{
+			CompareCharacter: options.Characters[i],
+			Sources:          o.Sources,
+		})
+
+		for stat, v := range secondaryStats(o.Statistics) {
+			comparison.SecondaryStats[stat] = append(comparison.SecondaryStats[stat], v)
+		}
+
+		levels := itemLevels(o.Equipment)
+		for _, slot := range append(slices.Clone(slots), "average") {
+			comparison.ItemLevels[slot] = append(comparison.ItemLevels[slot], &ComparedValueDTO{Value: levels[slot]})
+		}
+
+		score := &ComparedValueDTO{}
+		if o.RaiderIO != nil && len(o.RaiderIO.MythicPlusScoresBySeason) > 0 {
+			score.Value = float(o.RaiderIO.MythicPlusScoresBySeason[0].Scores.All)
+		}
+		comparison.MythicPlusScore = append(comparison.MythicPlusScore, score)
+
+		best, median := &ComparedValueDTO{}, &ComparedValueDTO{}
+		if o.Parses != nil && o.Parses.Summary != nil {
+			best.Value = o.Parses.Summary.BestPerformanceAverage
+			median.Value = o.Parses.Summary.MedianPerformanceAverage
+		}
+		comparison.Parses[ParseBestAverage] = append(comparison.Parses[ParseBestAverage], best)
+		comparison.Parses[ParseMedianAverage] = append(comparison.Parses[ParseMedianAverage], median)
+	}
+
+	for _, values := range comparison.SecondaryStats {
+		withDeltas(values)
+	}
+	for _, values := range comparison.ItemLevels {
+		withDeltas(values)
+	}
+	withDeltas(comparison.MythicPlusScore)
+	for _, values := range comparison.Parses {
+		withDeltas(values)
+	}
+
+	return comparison
+}
+
+// secondaryStats are the percentage & rating of each secondary stat, empty when there are no statistics. Crit &
+// haste are the best of their melee, ranged & spell values.
+func secondaryStats(statistics *bnet.CharacterStatisticsResponse) map[string]*ComparedValueDTO {
+	if statistics == nil {
+		return map[string]*ComparedValueDTO{
+			StatCrit:        {},
+			StatHaste:       {},
+			StatMastery:     {},
+			StatVersatility: {},
+		}
+	}
+
+	best := func(ratings ...bnet.StatisticRating) *ComparedValueDTO {
+		v := &ComparedValueDTO{}
+		for _, r := range ratings {
+			if r.Value != nil && (v.Value == nil || *r.Value > *v.Value) {
+				v.Value, v.Rating = float(*r.Value), float(r.Rating)
+			}
+		}
+
+		return v
+	}
+
+	return map[string]*ComparedValueDTO{
+		StatCrit:        best(statistics.MeleeCrit, statistics.RangedCrit, statistics.SpellCrit),
+		StatHaste:       best(statistics.MeleeHaste, statistics.RangedHaste, statistics.SpellHaste),
+		StatMastery:     best(statistics.Mastery),
+		StatVersatility: {Value: float(statistics.VersatilityDamageDone), Rating: float(statistics.Versatility)},
+	}
+}
+
+// itemLevels are the item level of each equipped slot, & their average, empty when there's no equipment.
+func itemLevels(equipment *bnet.CharacterEquipmentResponse) map[string]*float64 {
+	levels := map[string]*float64{}
+	if equipment == nil {
+		return levels
+	}
+
+	var total float64
+	for _, item := range equipment.EquippedItems {
+		if slices.Contains(cosmeticSlots, item.Slot.Type) {
+			continue
+		}
+
+		level := float64(item.Level.Value)
+		levels[item.Slot.Type] = &level
+		total += level
+	}
+
+	// A two-hander without an off hand counts for both.
+	if mainHand, ok := levels["MAIN_HAND"]; ok && levels["OFF_HAND"] == nil {
+		total += *mainHand
+	}
+	levels["average"] = float(total / averagedSlots)
+
+	return levels
+}
+
+// withDeltas sets the delta of each value against the first.
+func withDeltas(values []*ComparedValueDTO) {
+	if len(values) == 0 || values[0].Value == nil {
+		return
+	}
+
+	for _, v := range values {
+		if v.Value != nil {
+			v.Delta = float(*v.Value - *values[0].Value)
+		}
+	}
+}
+
+// float returns a pointer to the value, rounded to a single decimal place.
+func float(v float64) *float64 {
+	r := round(v)
+	return &r
+}
+
+// NewCharacterComparer creates a CharacterComparer comparing the overviews of the aggregator.
+func NewCharacterComparer(l hclog.Logger, overviews *OverviewAggregator) *CharacterComparer {
+	return &CharacterComparer{
+		l:         l,
+		overviews: overviews,
+	}
+}
@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeComparedSource is a profile, Raider.IO & Warcraft Logs source of two characters, the raider "skxyz" & the trial
+// "trial", whose Raider.IO profile can't be found.
+type fakeComparedSource struct {
+	fakeProfileSource
+}
+
+func (f *fakeComparedSource) CharacterEquipmentSummary(_ context.Context, options *bnet.CharacterOptions) (*bnet.CharacterEquipmentResponse, error) {
+	level := 639
+	if options.Character == "trial" {
+		level = 626
+	}
+
+	items := []bnet.CharacterEquipmentEquippedItem{{}, {}, {}}
+	items[0].Slot.Type, items[0].Level.Value = "HEAD", level
+	items[1].Slot.Type, items[1].Level.Value = "MAIN_HAND", level+3
+	items[2].Slot.Type, items[2].Level.Value = "SHIRT", 1
+
+	return &bnet.CharacterEquipmentResponse{EquippedItems: items}, nil
+}
+
+func (f *fakeComparedSource) CharacterStatistics(_ context.Context, options *bnet.CharacterOptions) (*bnet.CharacterStatisticsResponse, error) {
+	crit, spellCrit, mastery := 20.0, 25.5, 40.0
+	if options.Character == "trial" {
+		spellCrit, mastery = 22.5, 45.25
+	}
+
+	return &bnet.CharacterStatisticsResponse{
+		MeleeCrit:             bnet.StatisticRating{Rating: 1000, Value: &crit},
+		SpellCrit:             bnet.StatisticRating{Rating: 1500, Value: &spellCrit},
+		Mastery:               bnet.StatisticRating{Rating: 800, Value: &mastery},
+		Versatility:           600,
+		VersatilityDamageDone: 7.5,
+	}, nil
+}
+
+func (f *fakeComparedSource) CharacterProfile(_ context.Context, options *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error) {
+	if options.Character == "trial" {
+		return nil, errors.New("character not found")
+	}
+
+	profile := &rio.CharacterProfileResponse{MythicPlusScoresBySeason: make([]rio.MythicPlusSeasonScore, 1)}
+	profile.MythicPlusScoresBySeason[0].Scores.All = 2845.6
+
+	return profile, nil
+}
+
+func (f *fakeComparedSource) Ready() bool {
+	return true
+}
+
+// GetExpansionCatalog has no current raid, so without a zone neither character has parses.
+func (f *fakeComparedSource) GetExpansionCatalog(_ context.Context) (*wl.ExpansionCatalog, error) {
+	return wl.NewExpansionCatalog(nil), nil
+}
+
+func (f *fakeComparedSource) GetParsesForCharacter(_ context.Context, _ *wl.CharacterParsesQueryOptions) (*wl.CharacterParsesQuery, error) {
+	return &wl.CharacterParsesQuery{}, nil
+}
+
+func TestCharacterComparer_Compare(t *testing.T) {
+	source := &fakeComparedSource{}
+	comparer := NewCharacterComparer(hclog.NewNullLogger(), NewOverviewAggregator(hclog.NewNullLogger(), source, source, source))
+
+	comparison := comparer.Compare(context.Background(), &CompareOptions{Characters: []CompareCharacter{
+		{Region: "us", Realm: "illidan", Character: "skxyz"},
+		{Region: "eu", Realm: "draenor", Character: "trial"},
+	}})
+
+	assert.Len(t, comparison.Characters, 2)
+	assert.Equal(t, "draenor", comparison.Characters[1].Realm)
+	assert.Equal(t, SourceError, comparison.Characters[1].Sources[SectionRaiderIO].Status)
+	assert.Equal(t, SourceError, comparison.Characters[1].Sources[SectionParses].Status)
+	assert.NotContains(t, comparison.Characters[0].Sources, SectionSummary)
+
+	assert.Equal(t, []*ComparedValueDTO{
+		{Value: wl.Float(25.5), Rating: wl.Float(1500), Delta: wl.Float(0)},
+		{Value: wl.Float(22.5), Rating: wl.Float(1500), Delta: wl.Float(-3)},
+	}, comparison.SecondaryStats[StatCrit])
+	assert.Equal(t, wl.Float(5.3), comparison.SecondaryStats[StatMastery][1].Delta)
+	assert.Equal(t, wl.Float(7.5), comparison.SecondaryStats[StatVersatility][1].Value)
+	assert.Nil(t, comparison.SecondaryStats[StatHaste][0].Value)
+
+	assert.NotContains(t, comparison.ItemLevels, "SHIRT")
+	assert.Equal(t, wl.Float(-13), comparison.ItemLevels["HEAD"][1].Delta)
+	// The main hand counts for the off hand too, (639 + 642 * 2) / 16.
+	assert.Equal(t, wl.Float(120.2), comparison.ItemLevels["average"][0].Value)
+
+	assert.Equal(t, []*ComparedValueDTO{{Value: wl.Float(2845.6), Delta: wl.Float(0)}, {}}, comparison.MythicPlusScore)
+	assert.Equal(t, []*ComparedValueDTO{{}, {}}, comparison.Parses[ParseBestAverage])
+}
+
+func TestWithDeltas(t *testing.T) {
+	values := []*ComparedValueDTO{{}, {Value: wl.Float(10)}}
+	withDeltas(values)
+
+	assert.Nil(t, values[1].Delta)
+}
@@ -0,0 +1,397 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"math"
+	"slices"
+)
+
+const (
+	// keystoneBaseRating is the rating of timing a +2 exactly on time.
+	keystoneBaseRating = 165
+	// keystoneLevelRating is the rating each keystone level above +2 adds.
+	keystoneLevelRating = 15
+	// keystoneAffixRating is the rating each affix a keystone level adds.
+	keystoneAffixRating = 15
+	// keystoneTimerBonus is the most rating beating the timer adds, for beating it by 40% or more.
+	keystoneTimerBonus = 15
+	// maxPlannerLevels caps how many keystone levels a plan covers.
+	maxPlannerLevels = 10
+)
+
+// keystoneAffixLevels are the keystone levels a new affix is added at.
+var keystoneAffixLevels = []int{4, 7, 10, 12}
+
+// ErrInvalidPlannerLevels is returned when the keystone levels of the PlannerOptions can't be planned.
+var ErrInvalidPlannerLevels = errors.New("keystone levels must be between 2-30, with at most 10 levels planned")
+
+// KeystoneRating is the rating of timing a keystone of the level exactly on time.
+func KeystoneRating(level int) float64 {
+	rating := keystoneBaseRating + keystoneLevelRating*(level-2)
+	for _, l := range keystoneAffixLevels {
+		if level >= l {
+			rating += keystoneAffixRating
+		}
+	}
+
+	return float64(rating)
+}
+
+// KeystoneSource is the part of the BattlenetClient a character's keystone ratings come from.
+type KeystoneSource interface {
+	MythicKeystoneIndex(ctx context.Context, options *bnet.CharacterOptions) (*bnet.MythicKeystoneIndexResponse, error)
+	MythicKeystoneSeason(ctx context.Context, options *bnet.MythicSeasonOptions) (*bnet.MythicKeystoneSeasonResponse, error)
+}
+
+// MythicPlusSource is the part of the RaiderIOClient a character's runs & the season's dungeons come from.
+type MythicPlusSource interface {
+	CharacterProfile(ctx context.Context, options *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error)
+	MythicPlusAffixes(ctx context.Context, options *rio.AffixesOptions) (*rio.AffixesResponse, error)
+	MythicPlusStaticData(ctx context.Context, options *rio.StaticDataOptions) (*rio.StaticDataResponse, error)
+}
+
+// PlannerOptions are the character & keystone levels to plan.
+type PlannerOptions struct {
+	Region    string
+	Realm     string
+	Character string
+	// MinLevel & MaxLevel are the keystone levels planned, defaulting to around the highest key the character has timed.
+	MinLevel *int
+	MaxLevel *int
+}
+
+type MythicPlusPlanDTO struct {
+	Season string  `json:"season"`
+	Rating float64 `json:"rating"`
+	// WeeklyAffix is this week's Fortified or Tyrannical, only set when the season scores the best run of each.
+	WeeklyAffix string            `json:"weekly_affix,omitempty"`
+	MinLevel    int               `json:"min_level"`
+	MaxLevel    int               `json:"max_level"`
+	Dungeons    []*DungeonPlanDTO `json:"dungeons"`
+	// Ranked are the keys that gain rating, the most efficient first.
+	Ranked []*KeyPlanDTO `json:"ranked"`
+}
+
+type DungeonPlanDTO struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	ShortName string  `json:"short_name"`
+	Rating    float64 `json:"rating"`
+	BestLevel int     `json:"best_level,omitempty"`
+	BestTimed bool    `json:"best_timed"`
+	// AlternateLevel is the best level of the other weekly affix, when the season scores the best run of each.
+	AlternateLevel int           `json:"alternate_level,omitempty"`
+	Keys           []*KeyPlanDTO `json:"keys"`
+}
+
+type KeyPlanDTO struct {
+	Dungeon string `json:"dungeon"`
+	Level   int    `json:"level"`
+	// Rating is the rating of timing the key exactly on time.
+	Rating float64 `json:"rating"`
+	// Gain is the dungeon rating gained by timing the key exactly on time.
+	Gain float64 `json:"gain"`
+	// MaxGain is the dungeon rating gained by beating the timer by 40% or more.
+	MaxGain float64 `json:"max_gain"`
+	// Efficiency is the rating gained per keystone level.
+	Efficiency float64 `json:"efficiency"`
+}
+
+// MythicPlusPlanner works out how much rating a character gains from timing each dungeon at each keystone level.
+type MythicPlusPlanner struct {
+	l hclog.Logger
+
+	keystones  KeystoneSource
+	mythicPlus MythicPlusSource
+}
+
+// Plan gets the character's runs & ratings, planning the rating each dungeon of the season gains at each keystone
+// level of the options.
+func (p *MythicPlusPlanner) Plan(ctx context.Context, options *PlannerOptions) (*MythicPlusPlanDTO, error) {
+	profile, err := p.mythicPlus.CharacterProfile(ctx, &rio.CharacterProfileOptions{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+		Fields: []rio.ProfileField{
+			rio.FieldMythicPlusBestRuns,
+			rio.FieldMythicPlusAlternateRuns,
+			rio.FieldMythicPlusScoresBySeason.With(rio.SeasonCurrent),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &MythicPlusPlanDTO{
+		Dungeons: []*DungeonPlanDTO{},
+		Ranked:   []*KeyPlanDTO{},
+	}
+	if len(profile.MythicPlusScoresBySeason) > 0 {
+		plan.Season = profile.MythicPlusScoresBySeason[0].Season
+		plan.Rating = profile.MythicPlusScoresBySeason[0].Scores.All
+	}
+
+	plan.MinLevel, plan.MaxLevel, err = plannerLevels(profile, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Seasons with alternate runs score the best Fortified & Tyrannical run of each dungeon.
+	if len(profile.MythicPlusAlternateRuns) > 0 {
+		affixes, err := p.mythicPlus.MythicPlusAffixes(ctx, &rio.AffixesOptions{Region: options.Region, Locale: "en"})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range affixes.AffixDetails {
+			if a.Name == "Fortified" || a.Name == "Tyrannical" {
+				plan.WeeklyAffix = a.Name
+			}
+		}
+	}
+
+	mapRatings := p.mapRatings(ctx, options, plan)
+
+	for _, d := range p.dungeons(ctx, profile, plan.Season) {
+		dungeon := &DungeonPlanDTO{
+			ID:        d.ChallengeModeID,
+			Name:      d.Name,
+			ShortName: d.ShortName,
+			Keys:      []*KeyPlanDTO{},
+		}
+
+		scores := map[string]float64{}
+		if best := findRun(profile.MythicPlusBestRuns, d.ChallengeModeID); best != nil {
+			dungeon.BestLevel = best.MythicLevel
+			dungeon.BestTimed = best.NumKeystoneUpgrades > 0
+			scores[weeklyAffix(best)] = best.Score
+		}
+		if alternate := findRun(profile.MythicPlusAlternateRuns, d.ChallengeModeID); alternate != nil {
+			dungeon.AlternateLevel = alternate.MythicLevel
+			scores[weeklyAffix(alternate)] = alternate.Score
+		}
+
+		weighted := plan.WeeklyAffix != ""
+		if !weighted {
+			// Blizzard's rating of the dungeon is the source of truth when there's a single best run.
+			if rating, ok := mapRatings[d.ChallengeModeID]; ok {
+				scores = map[string]float64{"": rating}
+			}
+		}
+		dungeon.Rating = round(dungeonRating(scores, weighted))
+
+		for level := plan.MinLevel; level <= plan.MaxLevel; level++ {
+			rating := KeystoneRating(level)
+			key := &KeyPlanDTO{
+				Dungeon: d.ShortName,
+				Level:   level,
+				Rating:  rating,
+				Gain:    round(dungeonRating(withRun(scores, plan.WeeklyAffix, rating), weighted) - dungeonRating(scores, weighted)),
+				MaxGain: round(dungeonRating(withRun(scores, plan.WeeklyAffix, rating+keystoneTimerBonus), weighted) - dungeonRating(scores, weighted)),
+			}
+			key.Efficiency = round(key.Gain / float64(level))
+
+			dungeon.Keys = append(dungeon.Keys, key)
+			if key.Gain > 0 {
+				plan.Ranked = append(plan.Ranked, key)
+			}
+		}
+
+		plan.Dungeons = append(plan.Dungeons, dungeon)
+	}
+
+	slices.SortStableFunc(plan.Ranked, func(a, b *KeyPlanDTO) int {
+		if c := cmp.Compare(b.Efficiency, a.Efficiency); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Gain, a.Gain); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Level, b.Level)
+	})
+
+	return plan, nil
+}
+
+// mapRatings gets Blizzard's rating of each dungeon the character has run this season, keyed by the challenge mode ID.
+// Battle.net being unavailable only loses the ratings, the plan falls back to the Raider.IO scores.
+func (p *MythicPlusPlanner) mapRatings(ctx context.Context, options *PlannerOptions, plan *MythicPlusPlanDTO) map[int]float64 {
+	ratings := map[int]float64{}
+
+	character := bnet.CharacterOptions{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+	}
+
+	index, err := p.keystones.MythicKeystoneIndex(ctx, &character)
+	if err != nil {
+		p.l.Warn("MythicPlusPlanner failed to get the mythic keystone index", "character", options.Character, "error", err)
+		return ratings
+	}
+	if len(index.Seasons) == 0 {
+		return ratings
+	}
+
+	current := slices.MaxFunc(index.Seasons, func(a, b bnet.KeyedID) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	season, err := p.keystones.MythicKeystoneSeason(ctx, &bnet.MythicSeasonOptions{CharacterOptions: character, Season: current.ID})
+	if err != nil {
+		p.l.Warn("MythicPlusPlanner failed to get the mythic keystone season", "character", options.Character, "error", err)
+		return ratings
+	}
+
+	if season.MythicRating != nil {
+		plan.Rating = round(season.MythicRating.Rating)
+	}
+	for _, run := range season.BestRuns {
+		ratings[run.Dungeon.ID] = max(ratings[run.Dungeon.ID], run.MapRating.Rating)
+	}
+
+	return ratings
+}
+
+// dungeons are the dungeons of the season, falling back to the dungeons the character has run when the season isn't
+// known.
+func (p *MythicPlusPlanner) dungeons(ctx context.Context, profile *rio.CharacterProfileResponse, season string) []rio.StaticDungeon {
+	staticData, err := p.mythicPlus.MythicPlusStaticData(ctx, &rio.StaticDataOptions{ExpansionID: rio.LatestExpansionID})
+	if err != nil {
+		p.l.Warn("MythicPlusPlanner failed to get the mythic plus static data", "error", err)
+	} else {
+		for _, s := range staticData.Seasons {
+			if s.Slug == season {
+				return s.Dungeons
+			}
+		}
+	}
+
+	var dungeons []rio.StaticDungeon
+	for _, run := range slices.Concat(profile.MythicPlusBestRuns, profile.MythicPlusAlternateRuns) {
+		if !slices.ContainsFunc(dungeons, func(d rio.StaticDungeon) bool { return d.ChallengeModeID == run.MapChallengeModeID }) {
+			dungeons = append(dungeons, rio.StaticDungeon{
+				ID:              run.ZoneID,
+				ChallengeModeID: run.MapChallengeModeID,
+				Name:            run.Dungeon,
+				ShortName:       run.ShortName,
+			})
+		}
+	}
+
+	return dungeons
+}
+
+// plannerLevels are the keystone levels of the options, defaulting to one below, up to two above, the highest key the
+// character has timed.
+func plannerLevels(profile *rio.CharacterProfileResponse, options *PlannerOptions) (int, int, error) {
+	timed := 2
+	for _, run := range slices.Concat(profile.MythicPlusBestRuns, profile.MythicPlusAlternateRuns) {
+		if run.NumKeystoneUpgrades > 0 {
+			timed = max(timed, run.MythicLevel)
+		}
+	}
+
+	minLevel, maxLevel := max(2, timed-1), timed+2
+	if options.MinLevel != nil {
+		minLevel = *options.MinLevel
+	}
+	if options.MaxLevel != nil {
+		maxLevel = *options.MaxLevel
+	}
+	// A level given alone moves the default other to meet it, within the levels a plan covers.
+	if options.MinLevel != nil && options.MaxLevel == nil {
+		maxLevel = min(max(maxLevel, minLevel), minLevel+maxPlannerLevels-1)
+	}
+	if options.MaxLevel != nil && options.MinLevel == nil {
+		minLevel = max(min(minLevel, maxLevel), maxLevel-maxPlannerLevels+1)
+	}
+
+	if minLevel < 2 || maxLevel > 30 || minLevel > maxLevel || maxLevel-minLevel >= maxPlannerLevels {
+		return 0, 0, ErrInvalidPlannerLevels
+	}
+
+	return minLevel, maxLevel, nil
+}
+
+// findRun returns the run of the dungeon, or nil when there isn't one.
+func findRun(runs []rio.MythicPlusRun, challengeModeID int) *rio.MythicPlusRun {
+	for i := range runs {
+		if runs[i].MapChallengeModeID == challengeModeID {
+			return &runs[i]
+		}
+	}
+
+	return nil
+}
+
+// weeklyAffix returns whether the run was Fortified or Tyrannical.
+func weeklyAffix(run *rio.MythicPlusRun) string {
+	for _, a := range run.Affixes {
+		if a.Name == "Fortified" || a.Name == "Tyrannical" {
+			return a.Name
+		}
+	}
+
+	return ""
+}
+
+// withRun returns a copy of the scores with the run's score, if it's better than the one of the weekly affix.
+func withRun(scores map[string]float64, affix string, score float64) map[string]float64 {
+	// A single best run, whatever affix it was.
+	if affix == "" {
+		return map[string]float64{"": max(dungeonRating(scores, false), score)}
+	}
+
+	with := map[string]float64{}
+	for k, v := range scores {
+		with[k] = v
+	}
+	with[affix] = max(with[affix], score)
+
+	return with
+}
+
+// dungeonRating is the rating of a dungeon from its best scores. When weighted, the best score of the two weekly
+// affixes counts for 1.5 and the other for 0.5, otherwise the single best score counts.
+func dungeonRating(scores map[string]float64, weighted bool) float64 {
+	values := make([]float64, 0, len(scores))
+	for _, v := range scores {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	slices.Reverse(values)
+
+	if len(values) == 0 {
+		return 0
+	}
+	if !weighted {
+		return values[0]
+	}
+
+	rating := values[0] * 1.5
+	if len(values) > 1 {
+		rating += values[1] * 0.5
+	}
+
+	return rating
+}
+
+// round rounds to a single decimal place.
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// NewMythicPlusPlanner creates a MythicPlusPlanner getting the keystone ratings & runs from the sources.
+func NewMythicPlusPlanner(l hclog.Logger, keystones KeystoneSource, mythicPlus MythicPlusSource) *MythicPlusPlanner {
+	return &MythicPlusPlanner{
+		l:          l,
+		keystones:  keystones,
+		mythicPlus: mythicPlus,
+	}
+}
@@ -0,0 +1,208 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeKeystoneSource struct {
+	index  *bnet.MythicKeystoneIndexResponse
+	season *bnet.MythicKeystoneSeasonResponse
+	err    error
+}
+
+func (f *fakeKeystoneSource) MythicKeystoneIndex(_ context.Context, _ *bnet.CharacterOptions) (*bnet.MythicKeystoneIndexResponse, error) {
+	return f.index, f.err
+}
+
+func (f *fakeKeystoneSource) MythicKeystoneSeason(_ context.Context, _ *bnet.MythicSeasonOptions) (*bnet.MythicKeystoneSeasonResponse, error) {
+	return f.season, f.err
+}
+
+type fakeMythicPlusSource struct {
+	profile    *rio.CharacterProfileResponse
+	affixes    *rio.AffixesResponse
+	staticData *rio.StaticDataResponse
+}
+
+func (f *fakeMythicPlusSource) CharacterProfile(_ context.Context, _ *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error) {
+	return f.profile, nil
+}
+
+func (f *fakeMythicPlusSource) MythicPlusAffixes(_ context.Context, _ *rio.AffixesOptions) (*rio.AffixesResponse, error) {
+	return f.affixes, nil
+}
+
+func (f *fakeMythicPlusSource) MythicPlusStaticData(_ context.Context, _ *rio.StaticDataOptions) (*rio.StaticDataResponse, error) {
+	return f.staticData, nil
+}
+
+func mythicRun(challengeModeID int, shortName string, level, upgrades int, score float64, affixes ...string) rio.MythicPlusRun {
+	run := rio.MythicPlusRun{
+		ShortName:           shortName,
+		MythicLevel:         level,
+		NumKeystoneUpgrades: upgrades,
+		MapChallengeModeID:  challengeModeID,
+		Score:               score,
+	}
+	for _, a := range affixes {
+		run.Affixes = append(run.Affixes, rio.MythicPlusAffix{Name: a})
+	}
+
+	return run
+}
+
+func TestKeystoneRating(t *testing.T) {
+	assert.Equal(t, float64(165), KeystoneRating(2))
+	assert.Equal(t, float64(210), KeystoneRating(4))
+	assert.Equal(t, float64(330), KeystoneRating(10))
+	assert.Equal(t, float64(375), KeystoneRating(12))
+}
+
+func TestMythicPlusPlanner_Plan(t *testing.T) {
+	mythicPlus := &fakeMythicPlusSource{
+		profile: &rio.CharacterProfileResponse{
+			MythicPlusScoresBySeason: []rio.MythicPlusSeasonScore{{Season: "season-tww-1", Scores: rio.Scores{All: 1190}}},
+			MythicPlusBestRuns: []rio.MythicPlusRun{
+				mythicRun(503, "ARAK", 10, 1, 330),
+				mythicRun(501, "SV", 8, 0, 270),
+			},
+		},
+		staticData: &rio.StaticDataResponse{
+			Seasons: []rio.StaticSeason{{
+				Slug: "season-tww-1",
+				Dungeons: []rio.StaticDungeon{
+					{ChallengeModeID: 503, Name: "Ara-Kara, City of Echoes", ShortName: "ARAK"},
+					{ChallengeModeID: 501, Name: "The Stonevault", ShortName: "SV"},
+					{ChallengeModeID: 353, Name: "Siege of Boralus", ShortName: "SIEGE"},
+				},
+			}},
+		},
+	}
+
+	keystones := &fakeKeystoneSource{
+		index: &bnet.MythicKeystoneIndexResponse{Seasons: []bnet.KeyedID{{ID: 12}, {ID: 13}}},
+		season: &bnet.MythicKeystoneSeasonResponse{
+			BestRuns: []*bnet.MythicRun{
+				{Dungeon: bnet.NamedTypeAndID{KeyedID: bnet.KeyedID{ID: 503}}, MapRating: bnet.MythicRating{Rating: 335.2}},
+			},
+			MythicRating: &bnet.MythicRating{Rating: 1200.5},
+		},
+	}
+
+	plan, err := NewMythicPlusPlanner(hclog.NewNullLogger(), keystones, mythicPlus).Plan(context.Background(), &PlannerOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "season-tww-1", plan.Season)
+	assert.Equal(t, 1200.5, plan.Rating)
+	assert.Empty(t, plan.WeeklyAffix)
+	assert.Equal(t, 9, plan.MinLevel)
+	assert.Equal(t, 12, plan.MaxLevel)
+
+	assert.Len(t, plan.Dungeons, 3)
+
+	// Battle.net's rating of the dungeon is used over Raider.IO's score.
+	arak := plan.Dungeons[0]
+	assert.Equal(t, 335.2, arak.Rating)
+	assert.True(t, arak.BestTimed)
+	assert.Equal(t, []*KeyPlanDTO{
+		{Dungeon: "ARAK", Level: 9, Rating: 300, Gain: 0, MaxGain: 0, Efficiency: 0},
+		{Dungeon: "ARAK", Level: 10, Rating: 330, Gain: 0, MaxGain: 9.8, Efficiency: 0},
+		{Dungeon: "ARAK", Level: 11, Rating: 345, Gain: 9.8, MaxGain: 24.8, Efficiency: 0.9},
+		{Dungeon: "ARAK", Level: 12, Rating: 375, Gain: 39.8, MaxGain: 54.8, Efficiency: 3.3},
+	}, arak.Keys)
+
+	assert.Equal(t, float64(270), plan.Dungeons[1].Rating)
+	assert.False(t, plan.Dungeons[1].BestTimed)
+	assert.Equal(t, float64(0), plan.Dungeons[2].Rating)
+
+	// The dungeon never run is the most efficient, ties going to the bigger gain.
+	var ranked []string
+	for _, k := range plan.Ranked {
+		ranked = append(ranked, fmt.Sprintf("%s+%d", k.Dungeon, k.Level))
+	}
+	assert.Equal(t, []string{
+		"SIEGE+9", "SIEGE+10", "SIEGE+11", "SIEGE+12",
+		"SV+12", "SV+11", "SV+10",
+		"ARAK+12", "SV+9",
+		"ARAK+11",
+	}, ranked)
+}
+
+func TestMythicPlusPlanner_Plan_AlternateRuns(t *testing.T) {
+	mythicPlus := &fakeMythicPlusSource{
+		profile: &rio.CharacterProfileResponse{
+			MythicPlusScoresBySeason: []rio.MythicPlusSeasonScore{{Season: "season-df-4"}},
+			MythicPlusBestRuns:       []rio.MythicPlusRun{mythicRun(503, "ARAK", 4, 1, 100, "Fortified")},
+			MythicPlusAlternateRuns:  []rio.MythicPlusRun{mythicRun(503, "ARAK", 3, 1, 80, "Tyrannical")},
+		},
+		affixes: &rio.AffixesResponse{
+			AffixDetails: []rio.MythicPlusAffix{{Name: "Tyrannical"}, {Name: "Storming"}},
+		},
+		staticData: &rio.StaticDataResponse{},
+	}
+
+	// Battle.net being unavailable falls back to the Raider.IO scores.
+	keystones := &fakeKeystoneSource{err: errors.New("unavailable")}
+
+	level := 2
+	plan, err := NewMythicPlusPlanner(hclog.NewNullLogger(), keystones, mythicPlus).Plan(context.Background(), &PlannerOptions{
+		MinLevel: &level,
+		MaxLevel: &level,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Tyrannical", plan.WeeklyAffix)
+	assert.Len(t, plan.Dungeons, 1)
+
+	// 100 * 1.5 + 80 * 0.5, becoming 165 * 1.5 + 100 * 0.5 once the Tyrannical +2 is timed.
+	arak := plan.Dungeons[0]
+	assert.Equal(t, float64(190), arak.Rating)
+	assert.Equal(t, 3, arak.AlternateLevel)
+	assert.Equal(t, 107.5, arak.Keys[0].Gain)
+}
+
+func TestPlannerLevels(t *testing.T) {
+	profile := &rio.CharacterProfileResponse{
+		MythicPlusBestRuns: []rio.MythicPlusRun{
+			mythicRun(503, "ARAK", 12, 0, 0),
+			mythicRun(501, "SV", 10, 2, 0),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		min, max *int
+		want     [2]int
+		wantErr  bool
+	}{
+		{name: "around the highest timed", want: [2]int{9, 12}},
+		{name: "min above the default max", min: wl.Int(14), want: [2]int{14, 14}},
+		{name: "max below the default min", max: wl.Int(6), want: [2]int{6, 6}},
+		{name: "max above the default min", max: wl.Int(25), want: [2]int{16, 25}},
+		{name: "min & max", min: wl.Int(2), max: wl.Int(5), want: [2]int{2, 5}},
+		{name: "below 2", min: wl.Int(1), wantErr: true},
+		{name: "min above max", min: wl.Int(8), max: wl.Int(6), wantErr: true},
+		{name: "too many levels", min: wl.Int(2), max: wl.Int(20), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minLevel, maxLevel, err := plannerLevels(profile, &PlannerOptions{MinLevel: tt.min, MaxLevel: tt.max})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPlannerLevels)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, [2]int{minLevel, maxLevel})
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The statuses of a section of a CharacterOverviewDTO.
+const (
+	SourceOK          = "ok"
+	SourceError       = "error"
+	SourceTimeout     = "timeout"
+	SourceUnavailable = "unavailable"
+)
+
+// The sections of a CharacterOverviewDTO.
+const (
+	SectionSummary    = "summary"
+	SectionEquipment  = "equipment"
+	SectionMedia      = "media"
+	SectionStatistics = "statistics"
+	SectionRaiderIO   = "raiderio"
+	SectionParses     = "parses"
+)
+
+// OverviewTimeouts bound how long each upstream has to answer for a CharacterOverviewDTO, keyed by the sections.
+var OverviewTimeouts = map[string]time.Duration{
+	SectionSummary:    5 * time.Second,
+	SectionEquipment:  5 * time.Second,
+	SectionMedia:      5 * time.Second,
+	SectionStatistics: 5 * time.Second,
+	SectionRaiderIO:   5 * time.Second,
+	SectionParses:     10 * time.Second,
+}
+
+// ProfileSource is the part of the BattlenetClient a character's profile comes from.
+type ProfileSource interface {
+	CharacterSummary(ctx context.Context, options *bnet.CharacterOptions) (*bnet.CharacterSummaryResponse, error)
+	CharacterEquipmentSummary(ctx context.Context, options *bnet.CharacterOptions) (*bnet.CharacterEquipmentResponse, error)
+	CharacterMedia(ctx context.Context, options *bnet.CharacterOptions) (*bnet.CharacterMediaResponse, error)
+	CharacterStatistics(ctx context.Context, options *bnet.CharacterOptions) (*bnet.CharacterStatisticsResponse, error)
+}
+
+// CharacterProfileSource is the part of the RaiderIOClient a character's Raider.IO profile comes from.
+type CharacterProfileSource interface {
+	CharacterProfile(ctx context.Context, options *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error)
+}
+
+// ParsesSource is the part of the WarcraftLogsClient a character's parses come from.
+type ParsesSource interface {
+	Ready() bool
+	GetParsesForCharacter(ctx context.Context, options *wl.CharacterParsesQueryOptions) (*wl.CharacterParsesQuery, error)
+	GetExpansionCatalog(ctx context.Context) (*wl.ExpansionCatalog, error)
+}
+
+// OverviewOptions are the character to overview, & the Warcraft Logs zone of their parses.
+type OverviewOptions struct {
+	Region    string
+	Realm     string
+	Character string
+	// ZoneID is the zone of the parses, defaulting to the current raid.
+	ZoneID *int
+	// Sections are the only sections fetched, defaulting to every section.
+	Sections []string
+}
+
+type CharacterOverviewDTO struct {
+	Region     string                            `json:"region"`
+	Realm      string                            `json:"realm"`
+	Character  string                            `json:"character"`
+	Summary    *bnet.CharacterSummaryResponse    `json:"summary,omitempty"`
+	Equipment  *bnet.CharacterEquipmentResponse  `json:"equipment,omitempty"`
+	Media      *bnet.CharacterMediaResponse      `json:"media,omitempty"`
+	Statistics *bnet.CharacterStatisticsResponse `json:"statistics,omitempty"`
+	RaiderIO   *rio.CharacterProfileResponse     `json:"raiderio,omitempty"`
+	Parses     *wl.CharacterParseDTO             `json:"parses,omitempty"`
+	// Sources are the status of each section, keyed by the section.
+	Sources map[string]*SourceStatusDTO `json:"sources"`
+}
+
+// Partial reports whether any section failed.
+func (o *CharacterOverviewDTO) Partial() bool {
+	for _, s := range o.Sources {
+		if s.Status != SourceOK {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Failed reports whether every section failed.
+func (o *CharacterOverviewDTO) Failed() bool {
+	for _, s := range o.Sources {
+		if s.Status == SourceOK {
+			return false
+		}
+	}
+
+	return true
+}
+
+type SourceStatusDTO struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Duration is how long the section took, in milliseconds.
+	Duration int64 `json:"duration"`
+}
+
+// OverviewAggregator merges a character's Battle.net profile, Raider.IO profile & Warcraft Logs parses into a single
+// overview.
+type OverviewAggregator struct {
+	l hclog.Logger
+
+	profiles ProfileSource
+	raiderIO CharacterProfileSource
+	parses   ParsesSource
+	timeouts map[string]time.Duration
+}
+
+// Overview fetches every section of the overview at once, each within its timeout. A section failing only leaves it
+// out, with the reason in its status.
+func (a *OverviewAggregator) Overview(ctx context.Context, options *OverviewOptions) *CharacterOverviewDTO {
+	overview := &CharacterOverviewDTO{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+		Sources:   map[string]*SourceStatusDTO{},
+	}
+
+	character := &bnet.CharacterOptions{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+	}
+
+	sections := map[string]func(ctx context.Context) error{
+		SectionSummary: func(ctx context.Context) (err error) {
+			overview.Summary, err = a.profiles.CharacterSummary(ctx, character)
+			return err
+		},
+		SectionEquipment: func(ctx context.Context) (err error) {
+			overview.Equipment, err = a.profiles.CharacterEquipmentSummary(ctx, character)
+			return err
+		},
+		SectionMedia: func(ctx context.Context) (err error) {
+			overview.Media, err = a.profiles.CharacterMedia(ctx, character)
+			return err
+		},
+		SectionStatistics: func(ctx context.Context) (err error) {
+			overview.Statistics, err = a.profiles.CharacterStatistics(ctx, character)
+			return err
+		},
+		SectionRaiderIO: func(ctx context.Context) (err error) {
+			overview.RaiderIO, err = a.raiderIO.CharacterProfile(ctx, &rio.CharacterProfileOptions{
+				Region:    options.Region,
+				Realm:     options.Realm,
+				Character: options.Character,
+			})
+			return err
+		},
+		SectionParses: func(ctx context.Context) error {
+			zoneID, err := a.zoneID(ctx, options)
+			if err != nil {
+				return err
+			}
+
+			parses, err := a.parses.GetParsesForCharacter(ctx, &wl.CharacterParsesQueryOptions{
+				Name:         options.Character,
+				ServerSlug:   options.Realm,
+				ServerRegion: options.Region,
+				ZoneID:       zoneID,
+			})
+			if err != nil {
+				return err
+			}
+
+			overview.Parses = parses.ToDTO()
+			return nil
+		},
+	}
+
+	if len(options.Sections) > 0 {
+		for section := range sections {
+			if !slices.Contains(options.Sections, section) {
+				delete(sections, section)
+			}
+		}
+	}
+
+	if _, ok := sections[SectionParses]; ok && !a.parses.Ready() {
+		overview.Sources[SectionParses] = &SourceStatusDTO{Status: SourceUnavailable, Error: "warcraftlogs is currently unavailable"}
+		delete(sections, SectionParses)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for section, fetch := range sections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			status := a.fetch(ctx, section, fetch)
+			if status.Status != SourceOK {
+				a.l.Warn("OverviewAggregator failed to fetch a section", "section", section, "character", options.Character, "status", status.Status, "error", status.Error)
+			}
+
+			mu.Lock()
+			overview.Sources[section] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return overview
+}
+
+// zoneID is the zone of the options, or the current raid of the expansion catalog when they don't have one.
+func (a *OverviewAggregator) zoneID(ctx context.Context, options *OverviewOptions) (int, error) {
+	if options.ZoneID != nil {
+		return *options.ZoneID, nil
+	}
+
+	catalog, err := a.parses.GetExpansionCatalog(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	zone, err := catalog.CurrentRaid()
+	if err != nil {
+		return 0, err
+	}
+
+	return zone.ID, nil
+}
+
+// fetch runs the fetch of a section within the section's timeout, reporting how it went.
+func (a *OverviewAggregator) fetch(ctx context.Context, section string, fetch func(ctx context.Context) error) *SourceStatusDTO {
+	ctx, cancel := context.WithTimeout(ctx, a.timeouts[section])
+	defer cancel()
+
+	start := time.Now()
+	err := fetch(ctx)
+
+	status := &SourceStatusDTO{Status: SourceOK, Duration: time.Since(start).Milliseconds()}
+	switch {
+	case err == nil:
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		status.Status = SourceTimeout
+		status.Error = err.Error()
+	default:
+		status.Status = SourceError
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// NewOverviewAggregator creates an OverviewAggregator fetching the sections from the sources within the
+// OverviewTimeouts.
+func NewOverviewAggregator(l hclog.Logger, profiles ProfileSource, raiderIO CharacterProfileSource, parses ParsesSource) *OverviewAggregator {
+	return &OverviewAggregator{
+		l:        l,
+		profiles: profiles,
+		raiderIO: raiderIO,
+		parses:   parses,
+		timeouts: OverviewTimeouts,
+	}
+}
@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeProfileSource struct {
+	// mediaDelay holds up the media until the context is done.
+	mediaDelay bool
+}
+
+func (f *fakeProfileSource) CharacterSummary(_ context.Context, _ *bnet.CharacterOptions) (*bnet.CharacterSummaryResponse, error) {
+	return &bnet.CharacterSummaryResponse{Name: "Skxyz"}, nil
+}
+
+func (f *fakeProfileSource) CharacterEquipmentSummary(_ context.Context, _ *bnet.CharacterOptions) (*bnet.CharacterEquipmentResponse, error) {
+	return &bnet.CharacterEquipmentResponse{}, nil
+}
+
+func (f *fakeProfileSource) CharacterMedia(ctx context.Context, _ *bnet.CharacterOptions) (*bnet.CharacterMediaResponse, error) {
+	if f.mediaDelay {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return &bnet.CharacterMediaResponse{}, nil
+}
+
+func (f *fakeProfileSource) CharacterStatistics(_ context.Context, _ *bnet.CharacterOptions) (*bnet.CharacterStatisticsResponse, error) {
+	return &bnet.CharacterStatisticsResponse{}, nil
+}
+
+type fakeCharacterProfileSource struct {
+	err error
+}
+
+func (f *fakeCharacterProfileSource) CharacterProfile(_ context.Context, _ *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &rio.CharacterProfileResponse{CharacterInfo: rio.CharacterInfo{Name: "Skxyz"}}, nil
+}
+
+type fakeParsesSource struct {
+	ready bool
+}
+
+func (f *fakeParsesSource) Ready() bool {
+	return f.ready
+}
+
+func (f *fakeParsesSource) GetParsesForCharacter(_ context.Context, options *wl.CharacterParsesQueryOptions) (*wl.CharacterParsesQuery, error) {
+	if options.ZoneID != 38 {
+		return nil, wl.ErrZoneNotFound
+	}
+
+	return &wl.CharacterParsesQuery{}, nil
+}
+
+func (f *fakeParsesSource) GetExpansionCatalog(_ context.Context) (*wl.ExpansionCatalog, error) {
+	return wl.NewExpansionCatalog([]*wl.PartitionedExpansion{
+		{ID: 6, Zones: []wl.ExpansionZone{{ID: 38, Name: "Nerub-ar Palace"}, {ID: 39, Name: "Mythic+ Season 1"}}},
+	}), nil
+}
+
+func TestOverviewAggregator_Overview(t *testing.T) {
+	options := &OverviewOptions{Region: "us", Realm: "tichondrius", Character: "skxyz", ZoneID: wl.Int(38)}
+
+	overview := NewOverviewAggregator(hclog.NewNullLogger(), &fakeProfileSource{}, &fakeCharacterProfileSource{}, &fakeParsesSource{ready: true}).
+		Overview(context.Background(), options)
+
+	assert.Equal(t, "Skxyz", overview.Summary.Name)
+	assert.Equal(t, "Skxyz", overview.RaiderIO.Name)
+	assert.NotNil(t, overview.Parses)
+	assert.Len(t, overview.Sources, 6)
+	for section, s := range overview.Sources {
+		assert.Equal(t, SourceOK, s.Status, section)
+	}
+
+	assert.False(t, overview.Partial())
+	assert.False(t, overview.Failed())
+}
+
+func TestOverviewAggregator_Overview_Partial(t *testing.T) {
+	aggregator := NewOverviewAggregator(hclog.NewNullLogger(),
+		&fakeProfileSource{mediaDelay: true},
+		&fakeCharacterProfileSource{err: errors.New("character not found")},
+		&fakeParsesSource{ready: false})
+	aggregator.timeouts = map[string]time.Duration{
+		SectionSummary:    time.Second,
+		SectionEquipment:  time.Second,
+		SectionMedia:      10 * time.Millisecond,
+		SectionStatistics: time.Second,
+		SectionRaiderIO:   time.Second,
+	}
+
+	overview := aggregator.Overview(context.Background(), &OverviewOptions{Region: "us", Realm: "tichondrius", Character: "skxyz", ZoneID: wl.Int(38)})
+
+	assert.NotNil(t, overview.Summary)
+	assert.Nil(t, overview.Media)
+	assert.Nil(t, overview.RaiderIO)
+	assert.Nil(t, overview.Parses)
+
+	assert.Equal(t, SourceOK, overview.Sources[SectionSummary].Status)
+	assert.Equal(t, SourceTimeout, overview.Sources[SectionMedia].Status)
+	assert.Equal(t, &SourceStatusDTO{Status: SourceError, Error: "character not found"}, withoutDuration(overview.Sources[SectionRaiderIO]))
+	assert.Equal(t, SourceUnavailable, overview.Sources[SectionParses].Status)
+
+	assert.True(t, overview.Partial())
+	assert.False(t, overview.Failed())
+}
+
+func TestOverviewAggregator_Overview_CurrentRaid(t *testing.T) {
+	overview := NewOverviewAggregator(hclog.NewNullLogger(), &fakeProfileSource{}, &fakeCharacterProfileSource{}, &fakeParsesSource{ready: true}).
+		Overview(context.Background(), &OverviewOptions{Region: "us", Realm: "tichondrius", Character: "skxyz"})
+
+	// Without a zone, the parses are of the current raid.
+	assert.Equal(t, SourceOK, overview.Sources[SectionParses].Status)
+	assert.NotNil(t, overview.Parses)
+	assert.False(t, overview.Partial())
+}
+
+func TestOverviewAggregator_Overview_Sections(t *testing.T) {
+	overview := NewOverviewAggregator(hclog.NewNullLogger(), &fakeProfileSource{}, &fakeCharacterProfileSource{}, &fakeParsesSource{ready: false}).
+		Overview(context.Background(), &OverviewOptions{Region: "us", Realm: "tichondrius", Character: "skxyz", Sections: []string{SectionSummary, SectionRaiderIO}})
+
+	assert.NotNil(t, overview.Summary)
+	assert.NotNil(t, overview.RaiderIO)
+	assert.Nil(t, overview.Equipment)
+	assert.Len(t, overview.Sources, 2)
+}
+
+func TestCharacterOverviewDTO_Failed(t *testing.T) {
+	overview := &CharacterOverviewDTO{Sources: map[string]*SourceStatusDTO{
+		SectionSummary: {Status: SourceError},
+		SectionParses:  {Status: SourceUnavailable},
+	}}
+
+	assert.True(t, overview.Failed())
+	assert.True(t, overview.Partial())
+}
+
+func withoutDuration(s *SourceStatusDTO) *SourceStatusDTO {
+	c := *s
+	c.Duration = 0
+	return &c
+}
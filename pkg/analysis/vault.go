@@ -0,0 +1,397 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/calendar"
+	"github.com/heckin-dev/amashan/pkg/limiter"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxVaultConcurrency caps how many characters of a roster are looked up at once.
+const maxVaultConcurrency = 8
+
+// sameRunWindow is how far apart Battle.net & Raider.IO may have a run completing for it to be the same run.
+const sameRunWindow = time.Minute
+
+// currentSeasonExpansion is the name Battle.net groups the raids of the current season under.
+const currentSeasonExpansion = "Current Season"
+
+var (
+	// vaultDungeonThresholds are the keystones completed to unlock each dungeon slot of the Great Vault.
+	vaultDungeonThresholds = []int{1, 4, 8}
+	// vaultRaidThresholds are the bosses killed to unlock each raid slot of the Great Vault.
+	vaultRaidThresholds = []int{2, 4, 6}
+)
+
+// vaultKeystoneItemLevels are The War Within Season 1 item levels of a dungeon slot, indexed by the keystone level that
+// unlocked it. Keystones above +10 reward the same as a +10.
+var vaultKeystoneItemLevels = []int{2: 606, 3: 610, 4: 610, 5: 613, 6: 613, 7: 616, 8: 619, 9: 619, 10: 623}
+
+// vaultRaidDifficulties are the raid difficulties, easiest first, with The War Within Season 1 item levels of a raid
+// slot unlocked on each.
+var vaultRaidDifficulties = []struct {
+	Type      string
+	ItemLevel int
+}{
+	{Type: "LFR", ItemLevel: 571},
+	{Type: "NORMAL", ItemLevel: 584},
+	{Type: "HEROIC", ItemLevel: 597},
+	{Type: "MYTHIC", ItemLevel: 610},
+}
+
+// VaultSource is the part of the BattlenetClient a character's runs & kills of the week come from.
+type VaultSource interface {
+	MythicKeystoneIndex(ctx context.Context, options *bnet.CharacterOptions) (*bnet.MythicKeystoneIndexResponse, error)
+	CharacterRaidEncounters(ctx context.Context, options *bnet.CharacterOptions) (*bnet.CharacterRaidEncountersResponse, error)
+}
+
+type GreatVaultDTO struct {
+	Region    string `json:"region"`
+	Realm     string `json:"realm"`
+	Character string `json:"character"`
+	// Period is the mythic keystone period Battle.net reported the runs for.
+	Period      int       `json:"period"`
+	ResetAt     time.Time `json:"reset_at"`
+	NextResetAt time.Time `json:"next_reset_at"`
+	// Runs are the keystones completed since the reset, from the highest level runs Raider.IO has for the week & the
+	// best run of each dungeon Battle.net has. Mythic 0s aren't counted, neither source has them.
+	Runs      []*VaultRunDTO  `json:"runs"`
+	RaidKills []*VaultKillDTO `json:"raid_kills"`
+	Dungeons  []*VaultSlotDTO `json:"dungeons"`
+	Raid      []*VaultSlotDTO `json:"raid"`
+	// KeysNeeded are the keystones left to complete before every dungeon slot is unlocked.
+	KeysNeeded int `json:"keys_needed"`
+}
+
+type VaultRunDTO struct {
+	Dungeon     string    `json:"dungeon"`
+	Level       int       `json:"level"`
+	Timed       bool      `json:"timed"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+type VaultKillDTO struct {
+	Instance   string    `json:"instance"`
+	Encounter  string    `json:"encounter"`
+	Difficulty string    `json:"difficulty"`
+	KilledAt   time.Time `json:"killed_at"`
+}
+
+type VaultSlotDTO struct {
+	// Threshold is the runs or kills the slot is unlocked at, Progress how many the character has.
+	Threshold int  `json:"threshold"`
+	Progress  int  `json:"progress"`
+	Unlocked  bool `json:"unlocked"`
+	// KeystoneLevel & Difficulty are what an unlocked dungeon or raid slot rewards the item level of.
+	KeystoneLevel int    `json:"keystone_level,omitempty"`
+	Difficulty    string `json:"difficulty,omitempty"`
+	ItemLevel     int    `json:"item_level,omitempty"`
+}
+
+// VaultOptions are the character to track the Great Vault of.
+type VaultOptions struct {
+	Region    string `json:"region"`
+	Realm     string `json:"realm"`
+	Character string `json:"name"`
+}
+
+type VaultRosterDTO struct {
+	// Characters are ordered by the keys they still need, the most first.
+	Characters []*VaultRosterEntryDTO `json:"characters"`
+}
+
+type VaultRosterEntryDTO struct {
+	VaultOptions
+	KeysNeeded int            `json:"keys_needed"`
+	Vault      *GreatVaultDTO `json:"vault,omitempty"`
+	// Error is set, and Vault isn't, when the character couldn't be looked up.
+	Error string `json:"error,omitempty"`
+}
+
+// VaultTracker works out the Great Vault slots a character has unlocked since their region's weekly reset.
+type VaultTracker struct {
+	l hclog.Logger
+
+	source   VaultSource
+	raiderIO CharacterProfileSource
+	now      func() time.Time
+}
+
+// Vault gets the character's keystone runs & raid kills, working out the slots of their Great Vault for this week.
+func (v *VaultTracker) Vault(ctx context.Context, options *VaultOptions) (*GreatVaultDTO, error) {
+	now := v.now()
+
+	resetAt, err := calendar.WeeklyReset(options.Region, now)
+	if err != nil {
+		return nil, err
+	}
+	nextResetAt, _ := calendar.NextWeeklyReset(options.Region, now)
+
+	character := &bnet.CharacterOptions{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+	}
+
+	index, err := v.source.MythicKeystoneIndex(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+
+	// The runs are filtered by the reset either way, a disagreeing period only means the calendar needs a look.
+	if period, _ := calendar.MythicPeriod(options.Region, now); period != index.CurrentPeriod.Period.ID {
+		v.l.Warn("VaultTracker period disagrees with the mythic keystone index", "region", options.Region, "period", period, "current_period", index.CurrentPeriod.Period.ID)
+	}
+
+	// Battle.net's best runs are still something to go on without Raider.IO, repeated dungeons just go uncounted.
+	profile, err := v.raiderIO.CharacterProfile(ctx, &rio.CharacterProfileOptions{
+		Region:    options.Region,
+		Realm:     options.Realm,
+		Character: options.Character,
+		Fields:    []rio.ProfileField{rio.FieldMythicPlusWeeklyHighestLevelRuns},
+	})
+	if err != nil {
+		v.l.Warn("VaultTracker failed to get the weekly runs from Raider.IO", "region", options.Region, "realm", options.Realm, "character", options.Character, "error", err)
+	}
+
+	encounters, err := v.source.CharacterRaidEncounters(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+
+	vault := &GreatVaultDTO{
+		Region:      options.Region,
+		Realm:       options.Realm,
+		Character:   options.Character,
+		Period:      index.CurrentPeriod.Period.ID,
+		ResetAt:     resetAt,
+		NextResetAt: nextResetAt,
+		Runs:        weeklyRuns(index, profile, resetAt),
+		RaidKills:   weeklyKills(encounters, resetAt),
+	}
+
+	for _, threshold := range vaultDungeonThresholds {
+		slot := &VaultSlotDTO{Threshold: threshold, Progress: len(vault.Runs)}
+		if slot.Unlocked = slot.Progress >= threshold; slot.Unlocked {
+			slot.KeystoneLevel = vault.Runs[threshold-1].Level
+			slot.ItemLevel = vaultKeystoneItemLevels[min(slot.KeystoneLevel, len(vaultKeystoneItemLevels)-1)]
+		}
+		vault.Dungeons = append(vault.Dungeons, slot)
+	}
+
+	for _, threshold := range vaultRaidThresholds {
+		slot := &VaultSlotDTO{Threshold: threshold, Progress: len(vault.RaidKills)}
+		if slot.Unlocked = slot.Progress >= threshold; slot.Unlocked {
+			slot.Difficulty = vault.RaidKills[threshold-1].Difficulty
+			slot.ItemLevel = vaultRaidDifficulties[raidDifficultyRank(slot.Difficulty)].ItemLevel
+		}
+		vault.Raid = append(vault.Raid, slot)
+	}
+
+	vault.KeysNeeded = max(0, vaultDungeonThresholds[len(vaultDungeonThresholds)-1]-len(vault.Runs))
+
+	return vault, nil
+}
+
+// Roster tracks the Great Vault of each character, a few at a time as Bulk requests. A character that can't be looked
+// up has the error in its entry rather than failing the roster.
+func (v *VaultTracker) Roster(ctx context.Context, characters []VaultOptions) *VaultRosterDTO {
+	ctx = limiter.WithPriority(ctx, limiter.Bulk)
+
+	roster := &VaultRosterDTO{Characters: make([]*VaultRosterEntryDTO, len(characters))}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxVaultConcurrency)
+	for i, c := range characters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			entry := &VaultRosterEntryDTO{VaultOptions: c}
+			vault, err := v.Vault(ctx, &c)
+			if err != nil {
+				v.l.Warn("VaultTracker failed to track the great vault", "region", c.Region, "realm", c.Realm, "character", c.Character, "error", err)
+				entry.Error = err.Error()
+			} else {
+				entry.Vault = vault
+				entry.KeysNeeded = vault.KeysNeeded
+			}
+
+			roster.Characters[i] = entry
+		}()
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(roster.Characters, func(a, b *VaultRosterEntryDTO) int {
+		// Characters that failed have nothing to go on, so they go last.
+		if failedA, failedB := a.Error != "", b.Error != ""; failedA != failedB {
+			if failedA {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(b.KeysNeeded, a.KeysNeeded)
+	})
+
+	return roster
+}
+
+// weeklyRuns are the runs completed since the reset, the highest keystone first. Raider.IO has every run of the week
+// up to its highest ten, repeated dungeons included, Battle.net's best runs of the period fill in any Raider.IO has yet
+// to see.
+func weeklyRuns(index *bnet.MythicKeystoneIndexResponse, profile *rio.CharacterProfileResponse, resetAt time.Time) []*VaultRunDTO {
+	runs := []*VaultRunDTO{}
+	if profile != nil {
+		for _, run := range profile.MythicPlusWeeklyHighestLevelRuns {
+			if run.CompletedAt.Before(resetAt) {
+				continue
+			}
+
+			runs = append(runs, &VaultRunDTO{
+				Dungeon:     run.Dungeon,
+				Level:       run.MythicLevel,
+				Timed:       run.NumKeystoneUpgrades > 0,
+				CompletedAt: run.CompletedAt.UTC(),
+			})
+		}
+	}
+
+	for _, run := range index.CurrentPeriod.BestRuns {
+		completedAt := time.UnixMilli(int64(run.CompletedTimestamp)).UTC()
+		if completedAt.Before(resetAt) {
+			continue
+		}
+
+		seen := slices.ContainsFunc(runs, func(r *VaultRunDTO) bool {
+			return r.Level == run.KeystoneLevel && r.CompletedAt.Sub(completedAt).Abs() <= sameRunWindow
+		})
+		if seen {
+			continue
+		}
+
+		dungeon := ""
+		if run.Dungeon.Name != nil {
+			dungeon = *run.Dungeon.Name
+		}
+
+		runs = append(runs, &VaultRunDTO{
+			Dungeon:     dungeon,
+			Level:       run.KeystoneLevel,
+			Timed:       run.IsCompletedWithinTime,
+			CompletedAt: completedAt,
+		})
+	}
+
+	slices.SortStableFunc(runs, func(a, b *VaultRunDTO) int {
+		return cmp.Compare(b.Level, a.Level)
+	})
+
+	return runs
+}
+
+// weeklyKills are the bosses of the current season's raids killed since the reset, each at the hardest difficulty it
+// was killed on, the hardest first.
+func weeklyKills(encounters *bnet.CharacterRaidEncountersResponse, resetAt time.Time) []*VaultKillDTO {
+	kills := map[int]*VaultKillDTO{}
+
+	for _, instance := range currentSeasonInstances(encounters) {
+		instanceName := ""
+		if instance.Instance.Name != nil {
+			instanceName = *instance.Instance.Name
+		}
+
+		for _, mode := range instance.Modes {
+			rank := raidDifficultyRank(mode.Difficulty.Type)
+			if rank < 0 {
+				continue
+			}
+
+			for _, e := range mode.Progress.Encounters {
+				killedAt := time.UnixMilli(int64(e.LastKillTimestamp)).UTC()
+				if killedAt.Before(resetAt) {
+					continue
+				}
+
+				if kill, ok := kills[e.Encounter.ID]; ok && raidDifficultyRank(kill.Difficulty) >= rank {
+					continue
+				}
+
+				encounterName := ""
+				if e.Encounter.Name != nil {
+					encounterName = *e.Encounter.Name
+				}
+
+				kills[e.Encounter.ID] = &VaultKillDTO{
+					Instance:   instanceName,
+					Encounter:  encounterName,
+					Difficulty: mode.Difficulty.Type,
+					KilledAt:   killedAt,
+				}
+			}
+		}
+	}
+
+	sorted := make([]*VaultKillDTO, 0, len(kills))
+	for _, k := range kills {
+		sorted = append(sorted, k)
+	}
+	slices.SortFunc(sorted, func(a, b *VaultKillDTO) int {
+		if c := cmp.Compare(raidDifficultyRank(b.Difficulty), raidDifficultyRank(a.Difficulty)); c != 0 {
+			return c
+		}
+		return a.KilledAt.Compare(b.KilledAt)
+	})
+
+	return sorted
+}
+
+// currentSeasonInstances are the raids of the current season, falling back to the raids of the latest expansion when
+// Battle.net doesn't group them.
+func currentSeasonInstances(encounters *bnet.CharacterRaidEncountersResponse) []bnet.EncounterInstance {
+	var latest *bnet.EncounterExpansion
+	for i, e := range encounters.Expansions {
+		if e.Expansion.Name != nil && *e.Expansion.Name == currentSeasonExpansion {
+			return e.Instances
+		}
+		if latest == nil || e.Expansion.ID > latest.Expansion.ID {
+			latest = &encounters.Expansions[i]
+		}
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	return latest.Instances
+}
+
+// raidDifficultyRank is the index of the difficulty in vaultRaidDifficulties, or -1 when it isn't one.
+func raidDifficultyRank(difficulty string) int {
+	for i, d := range vaultRaidDifficulties {
+		if d.Type == difficulty {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NewVaultTracker creates a VaultTracker getting the runs & kills of characters from the source, and the rest of their
+// runs from Raider.IO.
+func NewVaultTracker(l hclog.Logger, source VaultSource, raiderIO CharacterProfileSource) *VaultTracker {
+	return &VaultTracker{
+		l:        l,
+		source:   source,
+		raiderIO: raiderIO,
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,219 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeVaultSource struct {
+	index      map[string]*bnet.MythicKeystoneIndexResponse
+	encounters *bnet.CharacterRaidEncountersResponse
+}
+
+func (f *fakeVaultSource) MythicKeystoneIndex(_ context.Context, options *bnet.CharacterOptions) (*bnet.MythicKeystoneIndexResponse, error) {
+	index, ok := f.index[options.Character]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return index, nil
+}
+
+func (f *fakeVaultSource) CharacterRaidEncounters(_ context.Context, _ *bnet.CharacterOptions) (*bnet.CharacterRaidEncountersResponse, error) {
+	return f.encounters, nil
+}
+
+// fakeWeeklyRunsSource has the Raider.IO weekly runs of characters, any other character isn't found.
+type fakeWeeklyRunsSource struct {
+	runs map[string][]rio.MythicPlusRun
+}
+
+func (f *fakeWeeklyRunsSource) CharacterProfile(_ context.Context, options *rio.CharacterProfileOptions) (*rio.CharacterProfileResponse, error) {
+	runs, ok := f.runs[options.Character]
+	if !ok {
+		return nil, errors.New("character not found")
+	}
+
+	return &rio.CharacterProfileResponse{MythicPlusWeeklyHighestLevelRuns: runs}, nil
+}
+
+// vaultNow is a Friday of the US week starting 2024-07-23 15:00 UTC.
+var vaultNow = time.Date(2024, 7, 26, 12, 0, 0, 0, time.UTC)
+
+func named(id int, name string) bnet.NamedTypeAndID {
+	return bnet.NamedTypeAndID{KeyedID: bnet.KeyedID{ID: id}, Name: &name}
+}
+
+func keystoneRun(dungeon string, level int, completedAt time.Time) bnet.MythicRun {
+	return bnet.MythicRun{
+		CompletedTimestamp:    uint64(completedAt.UnixMilli()),
+		KeystoneLevel:         level,
+		Dungeon:               named(level, dungeon),
+		IsCompletedWithinTime: true,
+	}
+}
+
+func weeklyRun(dungeon string, level int, completedAt time.Time) rio.MythicPlusRun {
+	return rio.MythicPlusRun{Dungeon: dungeon, MythicLevel: level, CompletedAt: completedAt, NumKeystoneUpgrades: 1}
+}
+
+// newWeeklyRunsSource has Raider.IO seeing repeats of Ara-Kara that Battle.net doesn't, but not yet the +4 & +6 that
+// Battle.net does.
+func newWeeklyRunsSource() *fakeWeeklyRunsSource {
+	return &fakeWeeklyRunsSource{
+		runs: map[string][]rio.MythicPlusRun{
+			"skkzr": {
+				weeklyRun("The Stonevault", 10, vaultNow.Add(-2*time.Hour).Add(20*time.Second)),
+				weeklyRun("Ara-Kara, City of Echoes", 8, vaultNow.Add(-time.Hour)),
+				weeklyRun("Ara-Kara, City of Echoes", 7, vaultNow.Add(-5*time.Hour)),
+				weeklyRun("Ara-Kara, City of Echoes", 7, vaultNow.Add(-6*time.Hour)),
+				weeklyRun("The Dawnbreaker", 12, vaultNow.AddDate(0, 0, -7)),
+			},
+		},
+	}
+}
+
+func encounterKill(id int, name string, killedAt time.Time) bnet.Encounter {
+	return bnet.Encounter{Encounter: named(id, name), CompletedCount: 1, LastKillTimestamp: uint64(killedAt.UnixMilli())}
+}
+
+func keystoneIndex(runs ...bnet.MythicRun) *bnet.MythicKeystoneIndexResponse {
+	index := &bnet.MythicKeystoneIndexResponse{}
+	index.CurrentPeriod.Period.ID = 969
+	index.CurrentPeriod.BestRuns = runs
+
+	return index
+}
+
+func newVaultSource() *fakeVaultSource {
+	lastWeek := vaultNow.AddDate(0, 0, -7)
+
+	return &fakeVaultSource{
+		index: map[string]*bnet.MythicKeystoneIndexResponse{
+			"skkzr": keystoneIndex(
+				keystoneRun("Ara-Kara, City of Echoes", 8, vaultNow.Add(-time.Hour)),
+				keystoneRun("The Stonevault", 10, vaultNow.Add(-2*time.Hour)),
+				keystoneRun("Siege of Boralus", 4, vaultNow.Add(-3*time.Hour)),
+				keystoneRun("Grim Batol", 6, vaultNow.Add(-4*time.Hour)),
+				keystoneRun("The Dawnbreaker", 12, lastWeek),
+			),
+			"imaag": keystoneIndex(),
+		},
+		encounters: &bnet.CharacterRaidEncountersResponse{
+			Expansions: []bnet.EncounterExpansion{
+				{
+					Expansion: named(503, "Dragonflight"),
+					Instances: []bnet.EncounterInstance{{
+						Instance: named(1207, "Amirdrassil, the Dream's Hope"),
+						Modes: []bnet.EncounterMode{{
+							Difficulty: bnet.TypeAndName{Type: "MYTHIC"},
+							Progress:   bnet.EncounterProgress{Encounters: []bnet.Encounter{encounterKill(2564, "Gnarlroot", vaultNow)}},
+						}},
+					}},
+				},
+				{
+					Expansion: named(505, "Current Season"),
+					Instances: []bnet.EncounterInstance{{
+						Instance: named(1273, "Nerub-ar Palace"),
+						Modes: []bnet.EncounterMode{
+							{
+								Difficulty: bnet.TypeAndName{Type: "NORMAL"},
+								Progress: bnet.EncounterProgress{Encounters: []bnet.Encounter{
+									encounterKill(2902, "Ulgrax the Devourer", vaultNow.Add(-48*time.Hour)),
+									encounterKill(2917, "The Bloodbound Horror", vaultNow.Add(-47*time.Hour)),
+									encounterKill(2898, "Sikran", vaultNow.Add(-46*time.Hour)),
+								}},
+							},
+							{
+								Difficulty: bnet.TypeAndName{Type: "HEROIC"},
+								Progress: bnet.EncounterProgress{Encounters: []bnet.Encounter{
+									encounterKill(2902, "Ulgrax the Devourer", vaultNow.Add(-24*time.Hour)),
+									encounterKill(2917, "The Bloodbound Horror", lastWeek),
+								}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestVaultTracker_Vault(t *testing.T) {
+	tracker := NewVaultTracker(hclog.NewNullLogger(), newVaultSource(), newWeeklyRunsSource())
+	tracker.now = func() time.Time { return vaultNow }
+
+	vault, err := tracker.Vault(context.Background(), &VaultOptions{Region: "us", Realm: "illidan", Character: "skkzr"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 969, vault.Period)
+	assert.Equal(t, time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC), vault.ResetAt)
+	assert.Equal(t, time.Date(2024, 7, 30, 15, 0, 0, 0, time.UTC), vault.NextResetAt)
+
+	// Repeated dungeons count, runs both sources have count once & last week's +12 doesn't count.
+	var levels []int
+	for _, r := range vault.Runs {
+		levels = append(levels, r.Level)
+	}
+	assert.Equal(t, []int{10, 8, 7, 7, 6, 4}, levels)
+	assert.Equal(t, 2, vault.KeysNeeded)
+
+	assert.Equal(t, []*VaultSlotDTO{
+		{Threshold: 1, Progress: 6, Unlocked: true, KeystoneLevel: 10, ItemLevel: 623},
+		{Threshold: 4, Progress: 6, Unlocked: true, KeystoneLevel: 7, ItemLevel: 616},
+		{Threshold: 8, Progress: 6},
+	}, vault.Dungeons)
+
+	// Only the current season's raids count, each boss at the hardest difficulty killed this week.
+	assert.Len(t, vault.RaidKills, 3)
+	assert.Equal(t, "Ulgrax the Devourer", vault.RaidKills[0].Encounter)
+	assert.Equal(t, "HEROIC", vault.RaidKills[0].Difficulty)
+	assert.Equal(t, "Nerub-ar Palace", vault.RaidKills[0].Instance)
+
+	assert.Equal(t, []*VaultSlotDTO{
+		{Threshold: 2, Progress: 3, Unlocked: true, Difficulty: "NORMAL", ItemLevel: 584},
+		{Threshold: 4, Progress: 3},
+		{Threshold: 6, Progress: 3},
+	}, vault.Raid)
+}
+
+func TestVaultTracker_Vault_WithoutRaiderIO(t *testing.T) {
+	tracker := NewVaultTracker(hclog.NewNullLogger(), newVaultSource(), &fakeWeeklyRunsSource{})
+	tracker.now = func() time.Time { return vaultNow }
+
+	vault, err := tracker.Vault(context.Background(), &VaultOptions{Region: "us", Realm: "illidan", Character: "skkzr"})
+	assert.NoError(t, err)
+
+	// Battle.net's best run of each dungeon is all there is to go on.
+	assert.Len(t, vault.Runs, 4)
+	assert.Equal(t, 4, vault.KeysNeeded)
+}
+
+func TestVaultTracker_Roster(t *testing.T) {
+	tracker := NewVaultTracker(hclog.NewNullLogger(), newVaultSource(), newWeeklyRunsSource())
+	tracker.now = func() time.Time { return vaultNow }
+
+	roster := tracker.Roster(context.Background(), []VaultOptions{
+		{Region: "us", Realm: "illidan", Character: "skkzr"},
+		{Region: "us", Realm: "illidan", Character: "unknown"},
+		{Region: "us", Realm: "illidan", Character: "imaag"},
+	})
+
+	assert.Len(t, roster.Characters, 3)
+
+	assert.Equal(t, "imaag", roster.Characters[0].Character)
+	assert.Equal(t, 8, roster.Characters[0].KeysNeeded)
+
+	assert.Equal(t, "skkzr", roster.Characters[1].Character)
+	assert.Equal(t, 2, roster.Characters[1].KeysNeeded)
+
+	assert.Equal(t, "unknown", roster.Characters[2].Character)
+	assert.Equal(t, "not found", roster.Characters[2].Error)
+	assert.Nil(t, roster.Characters[2].Vault)
+}
@@ -0,0 +1,379 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"slices"
+	"time"
+)
+
+const (
+	// firstDeathsPerWipe is how many of the earliest deaths of a wipe are recapped.
+	firstDeathsPerWipe = 3
+	// recapWindow is how long before a death the damage taken is attributed to it.
+	recapWindow = 10 * time.Second
+)
+
+// ReportSource is the part of the WarcraftLogsClient the analyses are built from.
+type ReportSource interface {
+	GetReport(ctx context.Context, code string) (*wl.ReportQuery, error)
+	GetReportAbilities(ctx context.Context, code string) (*wl.ReportAbilitiesQuery, error)
+	GetReportEvents(ctx context.Context, options *wl.ReportEventsOptions) ([]*wl.ReportEvent, error)
+	GetReportEventWindows(ctx context.Context, options *wl.ReportEventWindowsOptions) ([][]*wl.ReportEvent, error)
+}
+
+type WipeAnalysisDTO struct {
+	Code       string               `json:"code"`
+	Encounters []*EncounterWipesDTO `json:"encounters"`
+}
+
+type EncounterWipesDTO struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	Wipes            []*WipeDTO       `json:"wipes"`
+	KillingBlows     []*AbilityCount  `json:"killing_blows"`
+	TimeToFirstDeath *FirstDeathTrend `json:"time_to_first_death,omitempty"`
+}
+
+type WipeDTO struct {
+	FightID            int         `json:"fight_id"`
+	Pull               int         `json:"pull"`
+	DurationMS         uint64      `json:"duration_ms"`
+	BossPercentage     *float64    `json:"boss_percentage,omitempty"`
+	TimeToFirstDeathMS *uint64     `json:"time_to_first_death_ms,omitempty"`
+	FirstDeaths        []*DeathDTO `json:"first_deaths"`
+}
+
+type DeathDTO struct {
+	Player      *wl.ReportActorDTO    `json:"player"`
+	TimeMS      uint64                `json:"time_ms"`
+	KillingBlow *wl.TableAbilityDTO   `json:"killing_blow,omitempty"`
+	DamageTaken []*wl.TableAbilityDTO `json:"damage_taken"`
+}
+
+type AbilityCount struct {
+	Ability *wl.TableAbilityDTO `json:"ability"`
+	Count   int                 `json:"count"`
+}
+
+// FirstDeathTrend describes how the time to the first death changes from pull to pull.
+type FirstDeathTrend struct {
+	AverageMS uint64 `json:"average_ms"`
+	FirstMS   uint64 `json:"first_ms"`
+	LastMS    uint64 `json:"last_ms"`
+	// SlopeMSPerPull is the least squares slope, positive when the raid survives longer with each pull.
+	SlopeMSPerPull float64 `json:"slope_ms_per_pull"`
+}
+
+// WipeAnalyzer recaps the first deaths of every wipe in a report.
+type WipeAnalyzer struct {
+	l hclog.Logger
+
+	source ReportSource
+}
+
+// Analyze gets the wipes of the report for the given code, recapping their first deaths and the trend of the time to
+// the first death for each encounter.
+func (a *WipeAnalyzer) Analyze(ctx context.Context, code string) (*WipeAnalysisDTO, error) {
+	report, err := a.source.GetReport(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &WipeAnalysisDTO{
+		Code:       code,
+		Encounters: []*EncounterWipesDTO{},
+	}
+
+	wipes := wipesOf(report.ToDTO().Fights)
+	if len(wipes) == 0 {
+		return analysis, nil
+	}
+
+	abilities, err := a.source.GetReportAbilities(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	deaths, err := a.events(ctx, code, wl.DeathEvents, wipes)
+	if err != nil {
+		return nil, err
+	}
+
+	players := report.ReportData.Report.Players()
+
+	firstDeaths := byFight(deaths)
+	for id, d := range firstDeaths {
+		if len(d) > firstDeathsPerWipe {
+			firstDeaths[id] = d[:firstDeathsPerWipe]
+		}
+	}
+
+	damage, err := a.recapDamage(ctx, code, wipes, firstDeaths)
+	if err != nil {
+		return nil, err
+	}
+	damageByFight := byFight(damage)
+
+	encounters := map[int]*EncounterWipesDTO{}
+	for _, fight := range wipes {
+		e, ok := encounters[fight.EncounterID]
+		if !ok {
+			e = &EncounterWipesDTO{
+				ID:   fight.EncounterID,
+				Name: fight.Name,
+			}
+			encounters[fight.EncounterID] = e
+			analysis.Encounters = append(analysis.Encounters, e)
+		}
+
+		e.Wipes = append(e.Wipes, recapWipe(fight, len(e.Wipes)+1, firstDeaths[fight.ID], damageByFight[fight.ID], players, abilities.Abilities()))
+	}
+
+	for _, e := range analysis.Encounters {
+		e.KillingBlows = killingBlows(e.Wipes)
+		e.TimeToFirstDeath = firstDeathTrend(e.Wipes)
+	}
+
+	return analysis, nil
+}
+
+// events gets the events of the data type spanning all the given fights.
+func (a *WipeAnalyzer) events(ctx context.Context, code string, dataType wl.EventDataType, fights []*wl.ReportFightDTO) ([]*wl.ReportEvent, error) {
+	options := &wl.ReportEventsOptions{
+		Code:      code,
+		DataType:  dataType,
+		StartTime: float64(fights[0].StartTime),
+	}
+
+	for _, f := range fights {
+		options.FightIDs = append(options.FightIDs, f.ID)
+		options.EndTime = max(options.EndTime, float64(f.EndTime))
+	}
+
+	return a.source.GetReportEvents(ctx, options)
+}
+
+// recapDamage gets the damage taken of every wipe with a death, from the recap window of its first death until its
+// last recapped death. The windows are batched, so only the damage taken within them is paged through.
+func (a *WipeAnalyzer) recapDamage(ctx context.Context, code string, wipes []*wl.ReportFightDTO, firstDeaths map[int][]*wl.ReportEvent) ([]*wl.ReportEvent, error) {
+	options := &wl.ReportEventWindowsOptions{
+		Code:     code,
+		DataType: wl.DamageTakenEvents,
+	}
+
+	for _, fight := range wipes {
+		deaths := firstDeaths[fight.ID]
+		if len(deaths) == 0 {
+			continue
+		}
+
+		options.Windows = append(options.Windows, wl.ReportEventWindow{
+			FightID:   fight.ID,
+			StartTime: max(deaths[0].Timestamp-float64(recapWindow.Milliseconds()), float64(fight.StartTime)),
+			EndTime:   deaths[len(deaths)-1].Timestamp,
+		})
+	}
+
+	if len(options.Windows) == 0 {
+		return nil, nil
+	}
+
+	windows, err := a.source.GetReportEventWindows(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var damage []*wl.ReportEvent
+	for _, events := range windows {
+		damage = append(damage, events...)
+	}
+
+	return damage, nil
+}
+
+// wipesOf returns the encounter fights that weren't kills, in the order they were pulled.
+func wipesOf(fights []*wl.ReportFightDTO) []*wl.ReportFightDTO {
+	var wipes []*wl.ReportFightDTO
+	for _, f := range fights {
+		if f.EncounterID == 0 || f.Kill == nil || *f.Kill {
+			continue
+		}
+		wipes = append(wipes, f)
+	}
+
+	slices.SortFunc(wipes, func(a, b *wl.ReportFightDTO) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+
+	return wipes
+}
+
+// byFight groups the events by fight, ordered by timestamp.
+func byFight(events []*wl.ReportEvent) map[int][]*wl.ReportEvent {
+	grouped := map[int][]*wl.ReportEvent{}
+	for _, e := range events {
+		grouped[e.Fight] = append(grouped[e.Fight], e)
+	}
+
+	for _, group := range grouped {
+		slices.SortStableFunc(group, func(a, b *wl.ReportEvent) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+	}
+
+	return grouped
+}
+
+// recapWipe builds the recap of the first deaths of a wipe from the damage taken leading up to them.
+func recapWipe(fight *wl.ReportFightDTO, pull int, deaths, damage []*wl.ReportEvent, players map[int]*wl.ReportActorDTO, abilities map[int]*wl.TableAbilityDTO) *WipeDTO {
+	wipe := &WipeDTO{
+		FightID:        fight.ID,
+		Pull:           pull,
+		DurationMS:     fight.DurationMS,
+		BossPercentage: fight.BossPercentage,
+		FirstDeaths:    []*DeathDTO{},
+	}
+
+	for i, d := range deaths {
+		timeMS := uint64(max(d.Timestamp-float64(fight.StartTime), 0))
+		if i == 0 {
+			wipe.TimeToFirstDeathMS = &timeMS
+		}
+
+		death := &DeathDTO{
+			Player:      players[d.TargetID],
+			TimeMS:      timeMS,
+			DamageTaken: damageTaken(d, damage, abilities),
+		}
+
+		if death.Player == nil {
+			death.Player = &wl.ReportActorDTO{ID: d.TargetID}
+		}
+
+		killingAbility := d.AbilityGameID
+		if d.KillingAbilityGameID != nil {
+			killingAbility = *d.KillingAbilityGameID
+		}
+		if killingAbility != 0 {
+			death.KillingBlow = ability(abilities, killingAbility)
+		}
+
+		wipe.FirstDeaths = append(wipe.FirstDeaths, death)
+	}
+
+	return wipe
+}
+
+// damageTaken totals the damage taken by the player per ability in the recap window before their death.
+func damageTaken(death *wl.ReportEvent, damage []*wl.ReportEvent, abilities map[int]*wl.TableAbilityDTO) []*wl.TableAbilityDTO {
+	from := death.Timestamp - float64(recapWindow.Milliseconds())
+
+	totals := map[int]*wl.TableAbilityDTO{}
+	var result []*wl.TableAbilityDTO
+	for _, e := range damage {
+		if e.TargetID != death.TargetID || e.Timestamp < from || e.Timestamp > death.Timestamp {
+			continue
+		}
+
+		t, ok := totals[e.AbilityGameID]
+		if !ok {
+			t = ability(abilities, e.AbilityGameID)
+			totals[e.AbilityGameID] = t
+			result = append(result, t)
+		}
+		t.Total += e.Amount
+	}
+
+	slices.SortFunc(result, func(a, b *wl.TableAbilityDTO) int {
+		return -cmp.Compare(a.Total, b.Total)
+	})
+
+	return result
+}
+
+// ability copies the known ability for the game ID, or creates a bare one.
+func ability(abilities map[int]*wl.TableAbilityDTO, gameID int) *wl.TableAbilityDTO {
+	if a, ok := abilities[gameID]; ok {
+		return &wl.TableAbilityDTO{
+			GUID: a.GUID,
+			Name: a.Name,
+			Icon: a.Icon,
+		}
+	}
+
+	return &wl.TableAbilityDTO{GUID: int64(gameID)}
+}
+
+// killingBlows counts the killing blows of the first deaths across wipes, most common first.
+func killingBlows(wipes []*WipeDTO) []*AbilityCount {
+	counts := map[int64]*AbilityCount{}
+	result := []*AbilityCount{}
+	for _, w := range wipes {
+		for _, d := range w.FirstDeaths {
+			if d.KillingBlow == nil {
+				continue
+			}
+
+			c, ok := counts[d.KillingBlow.GUID]
+			if !ok {
+				c = &AbilityCount{Ability: d.KillingBlow}
+				counts[d.KillingBlow.GUID] = c
+				result = append(result, c)
+			}
+			c.Count++
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b *AbilityCount) int {
+		return -cmp.Compare(a.Count, b.Count)
+	})
+
+	return result
+}
+
+// firstDeathTrend fits the time to the first death of each pull with a least squares line.
+func firstDeathTrend(wipes []*WipeDTO) *FirstDeathTrend {
+	var xs, ys []float64
+	for _, w := range wipes {
+		if w.TimeToFirstDeathMS != nil {
+			xs = append(xs, float64(w.Pull))
+			ys = append(ys, float64(*w.TimeToFirstDeathMS))
+		}
+	}
+
+	if len(ys) == 0 {
+		return nil
+	}
+
+	n := float64(len(ys))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	trend := &FirstDeathTrend{
+		AverageMS: uint64(sumY / n),
+		FirstMS:   uint64(ys[0]),
+		LastMS:    uint64(ys[len(ys)-1]),
+	}
+
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		trend.SlopeMSPerPull = (n*sumXY - sumX*sumY) / denominator
+	}
+
+	return trend
+}
+
+// NewWipeAnalyzer creates a WipeAnalyzer getting its reports from the source.
+func NewWipeAnalyzer(l hclog.Logger, source ReportSource) *WipeAnalyzer {
+	return &WipeAnalyzer{
+		l:      l,
+		source: source,
+	}
+}
@@ -0,0 +1,219 @@
+package analysis
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hasura/go-graphql-client"
+	"github.com/heckin-dev/amashan/pkg/wl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeReportSource struct {
+	report    *wl.ReportQuery
+	abilities *wl.ReportAbilitiesQuery
+	events    map[wl.EventDataType][]*wl.ReportEvent
+	// queries counts the events queried for each data type.
+	queries map[wl.EventDataType]int
+	// maxEvents is the most events a query pages through before failing with wl.ErrTooManyEvents, unlimited when 0.
+	maxEvents int
+}
+
+func (f *fakeReportSource) GetReport(_ context.Context, _ string) (*wl.ReportQuery, error) {
+	if f.report == nil {
+		return nil, wl.ErrReportNotFound
+	}
+	return f.report, nil
+}
+
+func (f *fakeReportSource) GetReportAbilities(_ context.Context, _ string) (*wl.ReportAbilitiesQuery, error) {
+	return f.abilities, nil
+}
+
+func (f *fakeReportSource) GetReportEvents(_ context.Context, options *wl.ReportEventsOptions) ([]*wl.ReportEvent, error) {
+	f.queries[options.DataType]++
+
+	return f.between(options.DataType, options.FightIDs, options.StartTime, options.EndTime)
+}
+
+func (f *fakeReportSource) GetReportEventWindows(_ context.Context, options *wl.ReportEventWindowsOptions) ([][]*wl.ReportEvent, error) {
+	f.queries[options.DataType]++
+
+	var windows [][]*wl.ReportEvent
+	for _, w := range options.Windows {
+		events, err := f.between(options.DataType, []int{w.FightID}, w.StartTime, w.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, events)
+	}
+
+	return windows, nil
+}
+
+// between gets the events of the data type of the fights between the start & end time.
+func (f *fakeReportSource) between(dataType wl.EventDataType, fightIDs []int, start, end float64) ([]*wl.ReportEvent, error) {
+	var events []*wl.ReportEvent
+	for _, e := range f.events[dataType] {
+		if e.Timestamp < start || e.Timestamp > end {
+			continue
+		}
+
+		for _, id := range fightIDs {
+			if e.Fight == id {
+				events = append(events, e)
+			}
+		}
+	}
+
+	if f.maxEvents > 0 && len(events) > f.maxEvents {
+		return nil, wl.ErrTooManyEvents
+	}
+
+	return events, nil
+}
+
+func boolean(v bool) *graphql.Boolean {
+	b := graphql.Boolean(v)
+	return &b
+}
+
+func newFakeReportSource() *fakeReportSource {
+	return &fakeReportSource{
+		queries: map[wl.EventDataType]int{},
+		report: &wl.ReportQuery{
+			ReportData: wl.ReportData{
+				Report: &wl.Report{
+					ReportInfo: wl.ReportInfo{
+						Code: "abcd1234",
+						MasterData: &wl.ReportMasterData{
+							Actors: []wl.ReportActor{
+								{ID: 1, Name: "Aradin", SubType: "Paladin"},
+								{ID: 2, Name: "Skxyz", SubType: "Mage"},
+							},
+						},
+					},
+					Fights: []wl.ReportFight{
+						{ID: 1, Name: "Trash", StartTime: 0, EndTime: 1000},
+						{ID: 2, EncounterID: 2902, Name: "Ulgrax", StartTime: 10000, EndTime: 60000, Kill: boolean(false)},
+						{ID: 3, EncounterID: 2902, Name: "Ulgrax", StartTime: 70000, EndTime: 150000, Kill: boolean(false)},
+						{ID: 4, EncounterID: 2902, Name: "Ulgrax", StartTime: 160000, EndTime: 260000, Kill: boolean(true)},
+					},
+				},
+			},
+		},
+		abilities: &wl.ReportAbilitiesQuery{
+			ReportData: wl.ReportAbilitiesData{
+				Report: &wl.ReportAbilities{
+					MasterData: &struct{ Abilities []wl.ReportAbility }{
+						Abilities: []wl.ReportAbility{
+							{GameID: 100, Name: "Stalker's Netting"},
+							{GameID: 200, Name: "Venomous Lash"},
+						},
+					},
+				},
+			},
+		},
+		events: map[wl.EventDataType][]*wl.ReportEvent{
+			wl.DeathEvents: {
+				{Timestamp: 40000, Type: "death", Fight: 2, TargetID: 2, AbilityGameID: 100},
+				{Timestamp: 30000, Type: "death", Fight: 2, TargetID: 1, AbilityGameID: 100},
+				{Timestamp: 120000, Type: "death", Fight: 3, TargetID: 1, AbilityGameID: 200},
+			},
+			wl.DamageTakenEvents: {
+				{Timestamp: 15000, Type: "damage", Fight: 2, TargetID: 1, AbilityGameID: 200, Amount: 5000},
+				{Timestamp: 25000, Type: "damage", Fight: 2, TargetID: 1, AbilityGameID: 200, Amount: 1000},
+				{Timestamp: 29000, Type: "damage", Fight: 2, TargetID: 1, AbilityGameID: 100, Amount: 3000},
+				{Timestamp: 39000, Type: "damage", Fight: 2, TargetID: 2, AbilityGameID: 100, Amount: 4000},
+				{Timestamp: 118000, Type: "damage", Fight: 3, TargetID: 1, AbilityGameID: 200, Amount: 9000},
+			},
+		},
+	}
+}
+
+func TestWipeAnalyzer_Analyze(t *testing.T) {
+	source := newFakeReportSource()
+	a := NewWipeAnalyzer(hclog.NewNullLogger(), source)
+
+	analysis, err := a.Analyze(context.Background(), "abcd1234")
+	assert.NoError(t, err)
+	assert.Len(t, analysis.Encounters, 1)
+
+	// The damage taken of every wipe comes from a single query.
+	assert.Equal(t, map[wl.EventDataType]int{wl.DeathEvents: 1, wl.DamageTakenEvents: 1}, source.queries)
+
+	e := analysis.Encounters[0]
+	assert.Equal(t, 2902, e.ID)
+	assert.Len(t, e.Wipes, 2)
+
+	first := e.Wipes[0]
+	assert.Equal(t, 2, first.FightID)
+	assert.Equal(t, 1, first.Pull)
+	assert.Equal(t, uint64(20000), *first.TimeToFirstDeathMS)
+	assert.Len(t, first.FirstDeaths, 2)
+
+	death := first.FirstDeaths[0]
+	assert.Equal(t, "Aradin", death.Player.Name)
+	assert.Equal(t, "Stalker's Netting", death.KillingBlow.Name)
+
+	// Only the damage within the recap window counts, the biggest hitter first.
+	assert.Len(t, death.DamageTaken, 2)
+	assert.Equal(t, "Stalker's Netting", death.DamageTaken[0].Name)
+	assert.Equal(t, float64(3000), death.DamageTaken[0].Total)
+	assert.Equal(t, float64(1000), death.DamageTaken[1].Total)
+
+	assert.Equal(t, []*AbilityCount{
+		{Ability: &wl.TableAbilityDTO{GUID: 100, Name: "Stalker's Netting"}, Count: 2},
+		{Ability: &wl.TableAbilityDTO{GUID: 200, Name: "Venomous Lash"}, Count: 1},
+	}, e.KillingBlows)
+
+	assert.Equal(t, uint64(20000), e.TimeToFirstDeath.FirstMS)
+	assert.Equal(t, uint64(50000), e.TimeToFirstDeath.LastMS)
+	assert.Equal(t, uint64(35000), e.TimeToFirstDeath.AverageMS)
+	assert.Equal(t, float64(30000), e.TimeToFirstDeath.SlopeMSPerPull)
+
+	second := e.Wipes[1]
+	assert.Len(t, second.FirstDeaths, 1)
+	assert.Equal(t, float64(9000), second.FirstDeaths[0].DamageTaken[0].Total)
+}
+
+func TestWipeAnalyzer_Analyze_BusyReport(t *testing.T) {
+	source := newFakeReportSource()
+	source.maxEvents = 10
+
+	// Plenty of damage is taken outside the recap windows, more than a query pages through between the first & last
+	// recapped death of the report.
+	for ts := 41000; ts < 118000; ts += 1000 {
+		fight := 2
+		if ts >= 70000 {
+			fight = 3
+		}
+		source.events[wl.DamageTakenEvents] = append(source.events[wl.DamageTakenEvents],
+			&wl.ReportEvent{Timestamp: float64(ts), Type: "damage", Fight: fight, TargetID: 2, AbilityGameID: 200, Amount: 100})
+	}
+
+	analysis, err := NewWipeAnalyzer(hclog.NewNullLogger(), source).Analyze(context.Background(), "abcd1234")
+	assert.NoError(t, err)
+	assert.Equal(t, map[wl.EventDataType]int{wl.DeathEvents: 1, wl.DamageTakenEvents: 1}, source.queries)
+
+	wipes := analysis.Encounters[0].Wipes
+	assert.Equal(t, float64(3000), wipes[0].FirstDeaths[0].DamageTaken[0].Total)
+	assert.Equal(t, float64(9000), wipes[1].FirstDeaths[0].DamageTaken[0].Total)
+}
+
+func TestWipeAnalyzer_Analyze_NoWipes(t *testing.T) {
+	source := newFakeReportSource()
+	source.report.ReportData.Report.Fights = source.report.ReportData.Report.Fights[3:]
+
+	analysis, err := NewWipeAnalyzer(hclog.NewNullLogger(), source).Analyze(context.Background(), "abcd1234")
+	assert.NoError(t, err)
+	assert.Empty(t, analysis.Encounters)
+}
+
+func TestWipeAnalyzer_Analyze_NotFound(t *testing.T) {
+	source := newFakeReportSource()
+	source.report = nil
+
+	_, err := NewWipeAnalyzer(hclog.NewNullLogger(), source).Analyze(context.Background(), "abcd1234")
+	assert.ErrorIs(t, err, wl.ErrReportNotFound)
+}
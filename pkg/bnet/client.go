@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/limiter"
 	"golang.org/x/oauth2"
 	cc "golang.org/x/oauth2/clientcredentials"
 	"golang.org/x/time/rate"
@@ -31,8 +32,9 @@ type BattlenetClient struct {
 
 	clientConfig     *cc.Config
 	oauthConfig      *oauth2.Config
-	perSecondLimiter *rate.Limiter
-	perHourLimiter   *rate.Limiter
+	perSecondLimiter limiter.Limiter
+	perHourLimiter   limiter.Limiter
+	scheduler        *limiter.Scheduler
 
 	apiURLFn RegionalURLFunc
 }
@@ -467,6 +469,41 @@ func (b *BattlenetClient) RealmsByRegion(ctx context.Context, region RegionOptio
 	return riRes, nil
 }
 
+// MythicKeystonePeriodIndex gets the mythic keystone periods, and the current one, for the given region.
+func (b *BattlenetClient) MythicKeystonePeriodIndex(ctx context.Context, region RegionOption) (*MythicKeystonePeriodIndexResponse, error) {
+	// /data/wow/mythic-keystone/period/index
+	const endpoint = "/data/wow/mythic-keystone/period/index"
+
+	req, err := b.prepareRequest(&RequestOptions{
+		Region:    region.String(),
+		Namespace: DynamicNamespace,
+		Endpoint:  endpoint,
+		Method:    http.MethodGet,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.Do(ctx, nil, req, ClientRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	mkpiRes := &MythicKeystonePeriodIndexResponse{}
+	err = json.NewDecoder(res.Body).Decode(mkpiRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return mkpiRes, nil
+}
+
+// Close stops scheduling requests, those still waiting on the rate-limits fail.
+func (b *BattlenetClient) Close() {
+	b.scheduler.Close()
+}
+
 // Do does the provided *http.Request using the http.Client associated with the provided *oauth2.Token. This can be
 // used directly but there are likely other wrapper methods that are more useful.
 func (b *BattlenetClient) Do(ctx context.Context, t *oauth2.Token, req *http.Request, rType RequestType) (*http.Response, error) {
@@ -477,6 +514,11 @@ func (b *BattlenetClient) Do(ctx context.Context, t *oauth2.Token, req *http.Req
 		}
 	}
 
+	// The caller's context bounds the request itself, not only the wait for our turn.
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
 	// If we create a new context, we need to defer it.
 	var cancel context.CancelFunc
 	if ctx == nil {
@@ -484,13 +526,8 @@ func (b *BattlenetClient) Do(ctx context.Context, t *oauth2.Token, req *http.Req
 		defer cancel()
 	}
 
-	// Ensure we aren't exceeding the hourly rate limit.
-	if err := b.perHourLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-
-	// Ensure we aren't exceeding the per-second rate limit.
-	if err := b.perSecondLimiter.Wait(ctx); err != nil {
+	// Ensure we aren't exceeding the hourly & per-second rate limits, waiting our turn by priority.
+	if err := b.scheduler.Wait(ctx); err != nil {
 		return nil, err
 	}
 
@@ -511,8 +548,8 @@ func (b *BattlenetClient) Do(ctx context.Context, t *oauth2.Token, req *http.Req
 	}
 
 	if res.StatusCode == http.StatusTooManyRequests {
-		b.perSecondLimiter.ReserveN(time.Now().Add(1*time.Minute), b.perSecondLimiter.Burst())
-		b.perHourLimiter.ReserveN(time.Now().Add(1*time.Hour), b.perHourLimiter.Burst())
+		b.perSecondLimiter.Drain(1 * time.Minute)
+		b.perHourLimiter.Drain(1 * time.Hour)
 		b.l.Info("BattleNet Rate-Limit reached, drained remaining tokens")
 	}
 
@@ -574,6 +611,9 @@ func (b *BattlenetClient) prepareRequest(options *RequestOptions) (*http.Request
 }
 
 func NewBattlnetClient(l hclog.Logger) *BattlenetClient {
+	perSecondLimiter := limiter.New(l, "bnet:per-second", rate.Every(1*time.Second), 100) // 100/s
+	perHourLimiter := limiter.New(l, "bnet:per-hour", rate.Every(1*time.Hour), 36000)     // 36,000/h
+
 	return &BattlenetClient{
 		l: l,
 		clientConfig: &cc.Config{
@@ -591,8 +631,10 @@ func NewBattlnetClient(l hclog.Logger) *BattlenetClient {
 			RedirectURL: os.Getenv("BNET_REDIRECT_URL"),
 			Scopes:      []string{"wow.profile", "openid"},
 		},
-		perSecondLimiter: rate.NewLimiter(rate.Every(1*time.Second), 100), // 100/s
-		perHourLimiter:   rate.NewLimiter(rate.Every(1*time.Hour), 36000), // 36,000/h
+		perSecondLimiter: perSecondLimiter,
+		perHourLimiter:   perHourLimiter,
+		scheduler: limiter.NewScheduler(l, limiter.Chain{perHourLimiter, perSecondLimiter},
+			rate.Every(1*time.Second), 100, limiter.DefaultInteractiveReserve),
 		apiURLFn: func(region string) string {
 			return strings.Replace(BNET_API_URL, "{region}", region, -1)
 		},
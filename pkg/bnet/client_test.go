@@ -775,6 +775,17 @@ func TestBattlenetClient_RealmsByRegion(t *testing.T) {
 	}
 }
 
+func TestBattlenetClient_MythicKeystonePeriodIndex(t *testing.T) {
+	b, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := b.MythicKeystonePeriodIndex(nil, RegionsMap["us"])
+	assert.NoError(t, err)
+
+	assert.Equal(t, 969, got.CurrentPeriod.ID)
+	assert.Len(t, got.Periods, 4)
+}
+
 func toString(v string) *string {
 	return &v
 }
@@ -145,8 +145,21 @@ func (b *BattleNetMock) RealmIndex(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(res)
 }
 
+func (b *BattleNetMock) MythicKeystonePeriodIndex(w http.ResponseWriter, r *http.Request) {
+	res := &MythicKeystonePeriodIndexResponse{}
+	err := json.NewDecoder(bytes.NewReader(test.MythicKeystonePeriodIndex)).Decode(res)
+	if err != nil {
+		http.Error(w, "failed to decode test.MythicKeystonePeriodIndex", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
 func (b *BattleNetMock) Route(r *mux.Router) {
 	r.HandleFunc("/data/wow/realm/index", b.RealmIndex)
+	r.HandleFunc("/data/wow/mythic-keystone/period/index", b.MythicKeystonePeriodIndex)
 
 	publicProfile := r.PathPrefix("/profile/wow").Subrouter()
 	publicProfile.Use(middleware.UseRealm().Middleware)
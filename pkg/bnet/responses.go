@@ -246,8 +246,9 @@ type EncounterInstance struct {
 }
 
 type EncounterMode struct {
-	Difficulty TypeAndName `json:"difficulty"`
-	Status     TypeAndName `json:"status"`
+	Difficulty TypeAndName       `json:"difficulty"`
+	Status     TypeAndName       `json:"status"`
+	Progress   EncounterProgress `json:"progress"`
 }
 
 type EncounterProgress struct {
@@ -262,6 +263,12 @@ type Encounter struct {
 	LastKillTimestamp uint64         `json:"last_kill_timestamp"`
 }
 
+// MythicKeystonePeriodIndexResponse /data/wow/mythic-keystone/period/index
+type MythicKeystonePeriodIndexResponse struct {
+	Periods       []KeyedID `json:"periods"`
+	CurrentPeriod KeyedID   `json:"current_period"`
+}
+
 // RealmIndexResponse /data/wow/realm/index
 type RealmIndexResponse struct {
 	// Region is not  field is provided by Blizzard. We add this field for response clarity.
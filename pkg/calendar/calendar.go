@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"time"
+)
+
+// Regions are the regions a calendar can be worked out for.
+var Regions = []string{"us", "eu", "kr", "tw", "cn"}
+
+// PeriodSource is the part of the BattlenetClient the current mythic keystone period is checked against.
+type PeriodSource interface {
+	MythicKeystonePeriodIndex(ctx context.Context, region bnet.RegionOption) (*bnet.MythicKeystonePeriodIndexResponse, error)
+}
+
+// SeasonSource is the part of the RaiderIOClient the mythic plus seasons come from.
+type SeasonSource interface {
+	MythicPlusStaticData(ctx context.Context, options *rio.StaticDataOptions) (*rio.StaticDataResponse, error)
+}
+
+type CalendarDTO struct {
+	Region      string    `json:"region"`
+	ResetAt     time.Time `json:"reset_at"`
+	NextResetAt time.Time `json:"next_reset_at"`
+	Period      int       `json:"period"`
+	// BlizzardPeriod is Battle.net's current period, when it could be asked, and PeriodVerified whether it agrees with
+	// Period.
+	BlizzardPeriod *int       `json:"blizzard_period,omitempty"`
+	PeriodVerified bool       `json:"period_verified"`
+	Season         *SeasonDTO `json:"season,omitempty"`
+	NextSeason     *SeasonDTO `json:"next_season,omitempty"`
+}
+
+type SeasonDTO struct {
+	Slug   string     `json:"slug"`
+	Name   string     `json:"name"`
+	Starts *time.Time `json:"starts,omitempty"`
+	Ends   *time.Time `json:"ends,omitempty"`
+	// FirstPeriod is the period the season starts in.
+	FirstPeriod int `json:"first_period,omitempty"`
+	// Week is the week of the season the calendar is in, only set for the current season.
+	Week int `json:"week,omitempty"`
+}
+
+// Calendar works out the weekly reset, mythic keystone period & season of a region.
+type Calendar struct {
+	l hclog.Logger
+
+	periods PeriodSource
+	seasons SeasonSource
+	now     func() time.Time
+}
+
+// Region works out the region's calendar for now, checking the period against Battle.net's. Either source being
+// unavailable only loses what it adds.
+func (c *Calendar) Region(ctx context.Context, region string) (*CalendarDTO, error) {
+	now := c.now()
+
+	resetAt, err := WeeklyReset(region, now)
+	if err != nil {
+		return nil, err
+	}
+
+	nextResetAt, err := NextWeeklyReset(region, now)
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := MythicPeriod(region, now)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &CalendarDTO{
+		Region:      region,
+		ResetAt:     resetAt,
+		NextResetAt: nextResetAt,
+		Period:      period,
+	}
+
+	c.verifyPeriod(ctx, cal)
+
+	seasons := c.regionSeasons(ctx, region)
+	if current := CurrentSeason(seasons, now); current != nil {
+		cal.Season = seasonDTO(region, current)
+		cal.Season.Week = period - cal.Season.FirstPeriod + 1
+	}
+	if next := NextSeason(seasons, now); next != nil {
+		cal.NextSeason = seasonDTO(region, next)
+	}
+
+	return cal, nil
+}
+
+// verifyPeriod checks the calendar's period against Battle.net's current period of the region.
+func (c *Calendar) verifyPeriod(ctx context.Context, cal *CalendarDTO) {
+	region, ok := bnet.RegionsMap[cal.Region]
+	if !ok {
+		return
+	}
+
+	index, err := c.periods.MythicKeystonePeriodIndex(ctx, region)
+	if err != nil {
+		c.l.Warn("Calendar failed to get the mythic keystone period index", "region", cal.Region, "error", err)
+		return
+	}
+
+	cal.BlizzardPeriod = &index.CurrentPeriod.ID
+	cal.PeriodVerified = index.CurrentPeriod.ID == cal.Period
+	if !cal.PeriodVerified {
+		c.l.Warn("Calendar period disagrees with Battle.net", "region", cal.Region, "period", cal.Period, "blizzard_period", index.CurrentPeriod.ID)
+	}
+}
+
+// regionSeasons are the mythic plus seasons of the latest expansion, with the region's start & end.
+func (c *Calendar) regionSeasons(ctx context.Context, region string) []Season {
+	staticData, err := c.seasons.MythicPlusStaticData(ctx, &rio.StaticDataOptions{ExpansionID: rio.LatestExpansionID})
+	if err != nil {
+		c.l.Warn("Calendar failed to get the mythic plus static data", "error", err)
+		return nil
+	}
+
+	var seasons []Season
+	for _, s := range staticData.Seasons {
+		seasons = append(seasons, Season{
+			Slug:   s.Slug,
+			Name:   s.Name,
+			Starts: s.Starts[region],
+			Ends:   s.Ends[region],
+		})
+	}
+
+	return seasons
+}
+
+// seasonDTO converts the season, working out the region's period it starts in.
+func seasonDTO(region string, season *Season) *SeasonDTO {
+	dto := &SeasonDTO{
+		Slug:   season.Slug,
+		Name:   season.Name,
+		Starts: season.Starts,
+		Ends:   season.Ends,
+	}
+	if season.Starts != nil {
+		dto.FirstPeriod, _ = MythicPeriod(region, *season.Starts)
+	}
+
+	return dto
+}
+
+// NewCalendar creates a Calendar checking the period against, & getting the seasons from, the sources.
+func NewCalendar(l hclog.Logger, periods PeriodSource, seasons SeasonSource) *Calendar {
+	return &Calendar{
+		l:       l,
+		periods: periods,
+		seasons: seasons,
+		now:     time.Now,
+	}
+}
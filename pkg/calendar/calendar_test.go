@@ -0,0 +1,171 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/bnet"
+	"github.com/heckin-dev/amashan/pkg/rio"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakePeriodSource struct {
+	period int
+	err    error
+}
+
+func (f *fakePeriodSource) MythicKeystonePeriodIndex(_ context.Context, _ bnet.RegionOption) (*bnet.MythicKeystonePeriodIndexResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &bnet.MythicKeystonePeriodIndexResponse{CurrentPeriod: bnet.KeyedID{ID: f.period}}, nil
+}
+
+type fakeSeasonSource struct{}
+
+func (f *fakeSeasonSource) MythicPlusStaticData(_ context.Context, _ *rio.StaticDataOptions) (*rio.StaticDataResponse, error) {
+	return &rio.StaticDataResponse{
+		Seasons: []rio.StaticSeason{
+			{
+				Slug:   "season-tww-2",
+				Name:   "TWW Season 2",
+				Starts: map[string]*time.Time{"us": at(2025, 3, 4, 16), "eu": at(2025, 3, 5, 4)},
+				Ends:   map[string]*time.Time{"us": nil},
+			},
+			{
+				Slug:   "season-tww-1",
+				Name:   "TWW Season 1",
+				Starts: map[string]*time.Time{"us": at(2024, 9, 17, 15), "eu": at(2024, 9, 18, 4)},
+				Ends:   map[string]*time.Time{"us": at(2025, 2, 25, 16), "eu": at(2025, 2, 26, 4)},
+			},
+		},
+	}, nil
+}
+
+func at(year int, month time.Month, day, hour int) *time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestCalendar_Region(t *testing.T) {
+	tests := []struct {
+		name       string
+		region     string
+		now        time.Time
+		periods    *fakePeriodSource
+		want       *CalendarDTO
+		wantSeason *SeasonDTO
+		wantNext   string
+	}{
+		{
+			name:    "in season",
+			region:  "us",
+			now:     time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC),
+			periods: &fakePeriodSource{period: 979},
+			want: &CalendarDTO{
+				Region:         "us",
+				ResetAt:        time.Date(2024, 10, 1, 15, 0, 0, 0, time.UTC),
+				NextResetAt:    time.Date(2024, 10, 8, 15, 0, 0, 0, time.UTC),
+				Period:         979,
+				BlizzardPeriod: toInt(979),
+				PeriodVerified: true,
+			},
+			wantSeason: &SeasonDTO{Slug: "season-tww-1", Name: "TWW Season 1", FirstPeriod: 977, Week: 3},
+			wantNext:   "season-tww-2",
+		},
+		{
+			name:    "between seasons",
+			region:  "eu",
+			now:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			periods: &fakePeriodSource{period: 1000},
+			want: &CalendarDTO{
+				Region:         "eu",
+				ResetAt:        time.Date(2025, 2, 26, 4, 0, 0, 0, time.UTC),
+				NextResetAt:    time.Date(2025, 3, 5, 4, 0, 0, 0, time.UTC),
+				Period:         1000,
+				BlizzardPeriod: toInt(1000),
+				PeriodVerified: true,
+			},
+			wantNext: "season-tww-2",
+		},
+		{
+			name:    "battle.net unavailable",
+			region:  "us",
+			now:     time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+			periods: &fakePeriodSource{err: errors.New("unavailable")},
+			// Reset in winter time, the week ending in summer time.
+			want: &CalendarDTO{
+				Region:      "us",
+				ResetAt:     time.Date(2025, 3, 4, 16, 0, 0, 0, time.UTC),
+				NextResetAt: time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC),
+				Period:      1001,
+			},
+			wantSeason: &SeasonDTO{Slug: "season-tww-2", Name: "TWW Season 2", FirstPeriod: 1001, Week: 1},
+		},
+		{
+			name:    "china isn't checked",
+			region:  "cn",
+			now:     time.Date(2024, 7, 26, 0, 0, 0, 0, time.UTC),
+			periods: &fakePeriodSource{period: 1},
+			want: &CalendarDTO{
+				Region:      "cn",
+				ResetAt:     time.Date(2024, 7, 24, 23, 0, 0, 0, time.UTC),
+				NextResetAt: time.Date(2024, 7, 31, 23, 0, 0, 0, time.UTC),
+				Period:      969,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalendar(hclog.NewNullLogger(), tt.periods, &fakeSeasonSource{})
+			c.now = func() time.Time { return tt.now }
+
+			got, err := c.Region(context.Background(), tt.region)
+			assert.NoError(t, err)
+
+			season, next := got.Season, got.NextSeason
+			got.Season, got.NextSeason = nil, nil
+			assert.Equal(t, tt.want, got)
+
+			if tt.wantSeason == nil {
+				assert.Nil(t, season)
+			} else {
+				assert.Equal(t, tt.wantSeason.Slug, season.Slug)
+				assert.Equal(t, tt.wantSeason.FirstPeriod, season.FirstPeriod)
+				assert.Equal(t, tt.wantSeason.Week, season.Week)
+			}
+
+			if tt.wantNext == "" {
+				assert.Nil(t, next)
+			} else {
+				assert.Equal(t, tt.wantNext, next.Slug)
+				assert.Zero(t, next.Week)
+			}
+		})
+	}
+}
+
+func TestCalendar_Region_PeriodMismatch(t *testing.T) {
+	c := NewCalendar(hclog.NewNullLogger(), &fakePeriodSource{period: 980}, &fakeSeasonSource{})
+	c.now = func() time.Time { return time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC) }
+
+	got, err := c.Region(context.Background(), "us")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 979, got.Period)
+	assert.Equal(t, 980, *got.BlizzardPeriod)
+	assert.False(t, got.PeriodVerified)
+}
+
+func TestCalendar_Region_UnknownRegion(t *testing.T) {
+	_, err := NewCalendar(hclog.NewNullLogger(), &fakePeriodSource{}, &fakeSeasonSource{}).Region(context.Background(), "oc")
+	assert.ErrorIs(t, err, ErrUnknownRegion)
+}
+
+func toInt(v int) *int {
+	return &v
+}
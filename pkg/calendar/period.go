@@ -0,0 +1,36 @@
+package calendar
+
+import (
+	"math"
+	"time"
+)
+
+// periodAnchorID is a known mythic keystone period, starting at periodAnchorReset in the US. Periods are numbered the
+// same in every region, with the other regions resetting into a period within a day of the US.
+const periodAnchorID = 969
+
+var periodAnchorReset = time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC)
+
+// MythicPeriod is the ID of the region's mythic keystone period at t, counting the weekly resets since a known period.
+func MythicPeriod(region string, t time.Time) (int, error) {
+	resetAt, err := WeeklyReset(region, t)
+	if err != nil {
+		return 0, err
+	}
+
+	weeks := math.Floor(float64(resetAt.Sub(periodAnchorReset)) / float64(Week))
+
+	return periodAnchorID + int(weeks), nil
+}
+
+// PeriodStart is the instant the region's mythic keystone period started.
+func PeriodStart(region string, period int) (time.Time, error) {
+	if _, ok := weeklyResets[region]; !ok {
+		return time.Time{}, ErrUnknownRegion
+	}
+
+	// The region's reset within the week following the anchor's US reset.
+	anchor := periodAnchorReset.Add(time.Duration(period-periodAnchorID) * Week)
+
+	return WeeklyReset(region, anchor.Add(Week-time.Nanosecond))
+}
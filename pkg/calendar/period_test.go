@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMythicPeriod(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		t      time.Time
+		want   int
+		start  time.Time
+	}{
+		{
+			name:   "us anchor week",
+			region: "us",
+			t:      time.Date(2024, 7, 26, 4, 6, 0, 0, time.UTC),
+			want:   969,
+			start:  time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "eu before its reset",
+			region: "eu",
+			t:      time.Date(2024, 7, 23, 20, 0, 0, 0, time.UTC),
+			want:   968,
+			start:  time.Date(2024, 7, 17, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "tw season 1",
+			region: "tw",
+			t:      time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC),
+			want:   977,
+			start:  time.Date(2024, 9, 18, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us before the anchor",
+			region: "us",
+			t:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:   939,
+			start:  time.Date(2023, 12, 26, 16, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MythicPeriod(tt.region, tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			start, err := PeriodStart(tt.region, got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.start, start)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"errors"
+	"time"
+	// The reset time zones are embedded, so they load without the host's zoneinfo.
+	_ "time/tzdata"
+)
+
+// ErrUnknownRegion is returned for a region without a weekly reset.
+var ErrUnknownRegion = errors.New("calendar: unknown region")
+
+// Week is the length of a weekly reset period, give or take the hour of a daylight saving change.
+const Week = 7 * 24 * time.Hour
+
+// resetTime is the weekday & hour, in the region's time zone, a region's week resets at.
+type resetTime struct {
+	Weekday  time.Weekday
+	Hour     int
+	Location *time.Location
+}
+
+// weeklyResets are when each region's week resets. The US follows Pacific time, so its reset is an hour later in UTC
+// over the winter, while Europe resets at the same time in UTC all year. The regions of Asia all reset on Thursday
+// morning local time.
+var weeklyResets = map[string]resetTime{
+	"us": {Weekday: time.Tuesday, Hour: 8, Location: mustLoadLocation("America/Los_Angeles")},
+	"eu": {Weekday: time.Wednesday, Hour: 4, Location: time.UTC},
+	"kr": {Weekday: time.Thursday, Hour: 8, Location: mustLoadLocation("Asia/Seoul")},
+	"tw": {Weekday: time.Thursday, Hour: 7, Location: mustLoadLocation("Asia/Taipei")},
+	"cn": {Weekday: time.Thursday, Hour: 7, Location: mustLoadLocation("Asia/Shanghai")},
+}
+
+// mustLoadLocation loads the named time zone, panicking when it isn't known.
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
+
+// WeeklyReset is the instant, in UTC, the region's week containing t started, the latest reset at or before t.
+func WeeklyReset(region string, t time.Time) (time.Time, error) {
+	reset, ok := weeklyResets[region]
+	if !ok {
+		return time.Time{}, ErrUnknownRegion
+	}
+
+	t = t.In(reset.Location)
+	at := time.Date(t.Year(), t.Month(), t.Day(), reset.Hour, 0, 0, 0, reset.Location)
+	at = at.AddDate(0, 0, -int((t.Weekday()-reset.Weekday+7)%7))
+	if at.After(t) {
+		at = at.AddDate(0, 0, -7)
+	}
+
+	return at.UTC(), nil
+}
+
+// NextWeeklyReset is the instant, in UTC, the region's week containing t ends, the first reset after t.
+func NextWeeklyReset(region string, t time.Time) (time.Time, error) {
+	at, err := WeeklyReset(region, t)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := weeklyResets[region].Location
+
+	return at.In(loc).AddDate(0, 0, 7).UTC(), nil
+}
@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWeeklyReset(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		t      time.Time
+		want   time.Time
+		// next is the following reset, a week after want unless the clocks change in between.
+		next time.Time
+	}{
+		{
+			name:   "us mid-week",
+			region: "us",
+			t:      time.Date(2024, 7, 26, 4, 6, 0, 0, time.UTC),
+			want:   time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us just before reset",
+			region: "us",
+			t:      time.Date(2024, 7, 23, 14, 59, 59, 0, time.UTC),
+			want:   time.Date(2024, 7, 16, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us at reset",
+			region: "us",
+			t:      time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 7, 23, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us in winter",
+			region: "us",
+			t:      time.Date(2025, 1, 14, 15, 30, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 7, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us at reset in winter",
+			region: "us",
+			t:      time.Date(2025, 1, 14, 16, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 14, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us into winter",
+			region: "us",
+			t:      time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 10, 29, 15, 0, 0, 0, time.UTC),
+			next:   time.Date(2024, 11, 5, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us into summer",
+			region: "us",
+			t:      time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 3, 4, 16, 0, 0, 0, time.UTC),
+			next:   time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "eu in winter",
+			region: "eu",
+			t:      time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 15, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "eu on a tuesday",
+			region: "eu",
+			t:      time.Date(2024, 7, 23, 20, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 7, 17, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "kr in local time",
+			region: "kr",
+			t:      time.Date(2024, 7, 25, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60)),
+			want:   time.Date(2024, 7, 24, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WeeklyReset(tt.region, tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			if tt.next.IsZero() {
+				tt.next = tt.want.Add(Week)
+			}
+
+			next, err := NextWeeklyReset(tt.region, tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.next, next)
+		})
+	}
+}
+
+func TestWeeklyReset_UnknownRegion(t *testing.T) {
+	_, err := WeeklyReset("oc", time.Now())
+	assert.ErrorIs(t, err, ErrUnknownRegion)
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import "time"
+
+// Season is a mythic plus season in a region. Starts & Ends are nil until they're announced.
+type Season struct {
+	Slug   string
+	Name   string
+	Starts *time.Time
+	Ends   *time.Time
+}
+
+// Started reports whether the season had started by t.
+func (s *Season) Started(t time.Time) bool {
+	return s.Starts != nil && !s.Starts.After(t)
+}
+
+// Ended reports whether the season had ended by t.
+func (s *Season) Ended(t time.Time) bool {
+	return s.Ends != nil && !s.Ends.After(t)
+}
+
+// CurrentSeason is the season that started most recently by t without ending, or nil when between seasons.
+func CurrentSeason(seasons []Season, t time.Time) *Season {
+	var current *Season
+	for i, s := range seasons {
+		if !s.Started(t) || s.Ended(t) {
+			continue
+		}
+		if current == nil || s.Starts.After(*current.Starts) {
+			current = &seasons[i]
+		}
+	}
+
+	return current
+}
+
+// NextSeason is the announced season starting soonest after t, or nil when none is.
+func NextSeason(seasons []Season, t time.Time) *Season {
+	var next *Season
+	for i, s := range seasons {
+		if s.Starts == nil || !s.Starts.After(t) {
+			continue
+		}
+		if next == nil || s.Starts.Before(*next.Starts) {
+			next = &seasons[i]
+		}
+	}
+
+	return next
+}
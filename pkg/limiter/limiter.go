@@ -0,0 +1,73 @@
+package limiter
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket the API clients wait on before making an upstream request.
+type Limiter interface {
+	// Wait blocks until a token is available or the context is done.
+	Wait(ctx context.Context) error
+	// Drain spends every remaining token, refilling only after the given duration.
+	Drain(d time.Duration)
+}
+
+// Local is a Limiter local to the running process.
+type Local struct {
+	limiter *rate.Limiter
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *Local) Wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
+
+// Drain spends every remaining token, refilling only after the given duration.
+func (l *Local) Drain(d time.Duration) {
+	l.limiter.ReserveN(time.Now().Add(d), l.limiter.Burst())
+}
+
+// NewLocal creates a Local limiter refilling at r with a bucket of burst tokens.
+func NewLocal(r rate.Limit, burst int) *Local {
+	return &Local{
+		limiter: rate.NewLimiter(r, burst),
+	}
+}
+
+var (
+	sharedOnce   sync.Once
+	sharedClient *redis.Client
+)
+
+// Shared reports whether the upstream rate-limits should be shared through redis, set with SHARED_RATE_LIMITS.
+func Shared() bool {
+	shared, _ := strconv.ParseBool(os.Getenv("SHARED_RATE_LIMITS"))
+	return shared
+}
+
+// SharedClient returns the redis client used for shared limits, connecting on first use.
+func SharedClient(l hclog.Logger) *redis.Client {
+	sharedOnce.Do(func() {
+		sharedClient = utils.NewRedisClient(l)
+	})
+
+	return sharedClient
+}
+
+// New creates a Limiter for the given key, shared across instances through redis when Shared, local otherwise.
+func New(l hclog.Logger, key string, r rate.Limit, burst int) Limiter {
+	if !Shared() {
+		return NewLocal(r, burst)
+	}
+
+	l.Info("Using shared rate-limit", "key", key)
+	return NewRedis(l, SharedClient(l), key, r, burst)
+}
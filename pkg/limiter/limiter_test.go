@@ -0,0 +1,29 @@
+package limiter
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+	"testing"
+	"time"
+)
+
+func TestLocal_Drain(t *testing.T) {
+	l := NewLocal(rate.Every(time.Hour), 2)
+
+	assert.Nil(t, l.Wait(context.Background()))
+
+	l.Drain(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.NotNil(t, l.Wait(ctx))
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("SHARED_RATE_LIMITS", "false")
+
+	_, ok := New(nil, "test", rate.Every(time.Second), 1).(*Local)
+	assert.True(t, ok)
+}
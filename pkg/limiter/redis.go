@@ -0,0 +1,113 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+	"time"
+)
+
+// takeScript refills the bucket for the time elapsed since it was last touched, then either takes a token or
+// drains it. It returns the milliseconds to wait before trying again, 0 when a token was taken.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local drain = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) / 1000 * rate)
+	ts = now
+end
+
+local wait = 0
+if drain > 0 then
+	tokens = 0
+	ts = now + drain
+elseif now < ts then
+	wait = ts - now + math.ceil(1000 / rate)
+elseif tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + (ts - now) + 1000)
+
+return wait
+`)
+
+// Redis is a Limiter whose bucket is kept in redis, sharing it with every instance using the same key.
+type Redis struct {
+	l hclog.Logger
+
+	client *redis.Client
+	key    string
+	rate   rate.Limit
+	burst  int
+}
+
+// Wait blocks until a token is available or the context is done.
+func (r *Redis) Wait(ctx context.Context) error {
+	for {
+		wait, err := r.take(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		if wait == 0 {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return fmt.Errorf("rate: Wait(key=%s) would exceed context deadline", r.key)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Drain spends every remaining token, refilling only after the given duration.
+func (r *Redis) Drain(d time.Duration) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+
+	if _, err := r.take(ctx, d); err != nil {
+		r.l.Error("Failed to drain shared rate-limit", "key", r.key, "error", err)
+	}
+}
+
+// take runs the takeScript against the bucket, returning how long to wait before a token is available.
+func (r *Redis) take(ctx context.Context, drain time.Duration) (time.Duration, error) {
+	ms, err := takeScript.Run(ctx, r.client, []string{r.key},
+		float64(r.rate), r.burst, time.Now().UnixMilli(), drain.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// NewRedis creates a Redis limiter stored under key, refilling at r with a bucket of burst tokens.
+func NewRedis(l hclog.Logger, client *redis.Client, key string, r rate.Limit, burst int) *Redis {
+	return &Redis{
+		l:      l,
+		client: client,
+		key:    fmt.Sprintf("limiter:%s", key),
+		rate:   r,
+		burst:  burst,
+	}
+}
@@ -0,0 +1,332 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/time/rate"
+	"math"
+	"sync"
+	"time"
+)
+
+// Priority is the class of traffic a request to an upstream API belongs to.
+type Priority int
+
+const (
+	// Interactive requests are made on behalf of a user waiting on the response.
+	Interactive Priority = iota
+	// Background requests refresh data nobody is currently waiting on.
+	Background
+	// Bulk requests are large batches that can tolerate the most delay.
+	Bulk
+)
+
+func (p Priority) String() string {
+	switch p {
+	case Background:
+		return "background"
+	case Bulk:
+		return "bulk"
+	default:
+		return "interactive"
+	}
+}
+
+// ErrSchedulerClosed is returned to the requests waiting on a Scheduler once it is closed.
+var ErrSchedulerClosed = errors.New("limiter: scheduler closed")
+
+// DefaultInteractiveReserve is the share of an upstream rate-limit kept for Interactive requests.
+const DefaultInteractiveReserve = 0.2
+
+// weights are the turns each Priority gets per round when Background and Bulk requests are both waiting.
+var weights = map[Priority]int{
+	Background: 3,
+	Bulk:       1,
+}
+
+type priorityContextKey struct{}
+
+// WithPriority returns a copy of the context whose upstream requests are scheduled with the given Priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the Priority for the context, defaulting to Interactive.
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return Interactive
+	}
+
+	if p, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return p
+	}
+
+	return Interactive
+}
+
+type waiter struct {
+	priority Priority
+	turn     chan struct{}
+	done     chan struct{}
+}
+
+// Scheduler is a Limiter handing out turns at an upstream Limiter by Priority. Interactive requests always go first,
+// Background and Bulk requests share the rest by weight, and may only use the share of the rate not reserved for
+// Interactive requests.
+type Scheduler struct {
+	l hclog.Logger
+
+	limiter Limiter
+	share   *rate.Limiter
+
+	mu      sync.Mutex
+	queues  map[Priority][]*waiter
+	credits map[Priority]int
+	wake    chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Wait blocks until it is the turn of the context's Priority and the upstream Limiter has a token, or the Scheduler
+// is closed.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	select {
+	case <-s.closed:
+		return ErrSchedulerClosed
+	default:
+	}
+
+	w := &waiter{
+		priority: PriorityFromContext(ctx),
+		turn:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	s.enqueue(w)
+
+	select {
+	case <-w.turn:
+		defer close(w.done)
+		return s.limiter.Wait(ctx)
+	case <-ctx.Done():
+		s.giveUp(w)
+		return ctx.Err()
+	case <-s.closed:
+		s.giveUp(w)
+		return ErrSchedulerClosed
+	}
+}
+
+// Close stops handing out turns, the requests still waiting for theirs failing with ErrSchedulerClosed.
+func (s *Scheduler) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+// Drain spends every remaining token of the upstream Limiter.
+func (s *Scheduler) Drain(d time.Duration) {
+	s.limiter.Drain(d)
+}
+
+// Waiting returns the number of requests queued for each Priority.
+func (s *Scheduler) Waiting() map[Priority]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waiting := map[Priority]int{}
+	for p, q := range s.queues {
+		waiting[p] = len(q)
+	}
+
+	return waiting
+}
+
+func (s *Scheduler) enqueue(w *waiter) {
+	s.mu.Lock()
+	s.queues[w.priority] = append(s.queues[w.priority], w)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// giveUp takes the waiter out of its queue, passing its turn along if it has already been handed it.
+func (s *Scheduler) giveUp(w *waiter) {
+	s.mu.Lock()
+	for i, qw := range s.queues[w.priority] {
+		if qw == w {
+			s.queues[w.priority] = append(s.queues[w.priority][:i], s.queues[w.priority][i+1:]...)
+			s.mu.Unlock()
+			return
+		}
+	}
+	s.mu.Unlock()
+
+	<-w.turn
+	close(w.done)
+}
+
+// run hands out turns one at a time, waiting for each to get its upstream token before handing out the next, until
+// the Scheduler is closed.
+func (s *Scheduler) run() {
+	for {
+		w := s.next()
+		if w == nil {
+			return
+		}
+		close(w.turn)
+
+		if w.priority == Interactive {
+			select {
+			case <-w.done:
+			case <-s.closed:
+				return
+			}
+			continue
+		}
+
+		if !s.interactiveAhead(w) {
+			return
+		}
+	}
+}
+
+// interactiveAhead hands out turns to the Interactive requests showing up while the Background or Bulk waiter waits
+// for its upstream token, so they aren't queued behind it. It returns false once the Scheduler is closed.
+func (s *Scheduler) interactiveAhead(w *waiter) bool {
+	for {
+		select {
+		case <-w.done:
+			return true
+		case <-s.closed:
+			return false
+		case <-s.wake:
+			s.mu.Lock()
+			for len(s.queues[Interactive]) > 0 {
+				close(s.pop(Interactive).turn)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// next blocks until there is a waiter whose turn it is, or returns nil once the Scheduler is closed.
+func (s *Scheduler) next() *waiter {
+	for {
+		select {
+		case <-s.closed:
+			return nil
+		default:
+		}
+
+		s.mu.Lock()
+		if len(s.queues[Interactive]) > 0 {
+			w := s.pop(Interactive)
+			s.mu.Unlock()
+			return w
+		}
+
+		p, ok := s.pickShared()
+		if !ok {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+			case <-s.closed:
+			}
+			continue
+		}
+
+		r := s.share.Reserve()
+		if r.Delay() == 0 {
+			s.credits[p]--
+			w := s.pop(p)
+			s.mu.Unlock()
+			return w
+		}
+
+		// Out of the shared rate, wait for it to refill unless an Interactive request shows up.
+		delay := r.Delay()
+		r.Cancel()
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		case <-s.closed:
+			timer.Stop()
+		}
+	}
+}
+
+// pickShared returns the Background or Bulk Priority due the next turn, refilling their credits each round.
+func (s *Scheduler) pickShared() (Priority, bool) {
+	for round := 0; round < 2; round++ {
+		for _, p := range []Priority{Background, Bulk} {
+			if len(s.queues[p]) > 0 && s.credits[p] > 0 {
+				return p, true
+			}
+		}
+
+		for p, weight := range weights {
+			s.credits[p] = weight
+		}
+	}
+
+	return 0, false
+}
+
+func (s *Scheduler) pop(p Priority) *waiter {
+	w := s.queues[p][0]
+	s.queues[p] = s.queues[p][1:]
+	return w
+}
+
+// NewScheduler creates a Scheduler in front of the Limiter, which refills at r with a bucket of burst tokens. The
+// reserve is the share of r and burst only Interactive requests may use. It hands out turns until it is closed.
+func NewScheduler(l hclog.Logger, limiter Limiter, r rate.Limit, burst int, reserve float64) *Scheduler {
+	share := 1 - reserve
+	s := &Scheduler{
+		l:       l,
+		limiter: limiter,
+		share:   rate.NewLimiter(r*rate.Limit(share), int(math.Max(1, float64(burst)*share))),
+		queues:  map[Priority][]*waiter{},
+		credits: map[Priority]int{},
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Chain is a Limiter waiting on each of its Limiters in order, e.g. an hourly limit before a per-second limit.
+type Chain []Limiter
+
+// Wait blocks until each Limiter has a token.
+func (c Chain) Wait(ctx context.Context) error {
+	for _, l := range c {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Drain spends every remaining token of each Limiter.
+func (c Chain) Drain(d time.Duration) {
+	for _, l := range c {
+		l.Drain(d)
+	}
+}
@@ -0,0 +1,127 @@
+package limiter
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gate is a Limiter handing out a token each time it is opened.
+type gate chan struct{}
+
+func (g gate) Wait(ctx context.Context) error {
+	select {
+	case <-g:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g gate) Drain(time.Duration) {}
+
+// recorder is a gate recording the Priority of each request reaching it, in the order they reach it.
+type recorder struct {
+	gate
+
+	mu      sync.Mutex
+	reached []Priority
+}
+
+func (r *recorder) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	r.reached = append(r.reached, PriorityFromContext(ctx))
+	r.mu.Unlock()
+
+	return r.gate.Wait(ctx)
+}
+
+func (r *recorder) order() []Priority {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.reached)
+}
+
+func TestScheduler_Wait(t *testing.T) {
+	r := &recorder{gate: make(gate)}
+	s := NewScheduler(hclog.Default(), r, rate.Inf, 10, DefaultInteractiveReserve)
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	wait := func(p Priority) {
+		defer wg.Done()
+		assert.NoError(t, s.Wait(WithPriority(context.Background(), p)))
+	}
+
+	// The first request holds the turn until the gate opens, the rest queue behind it.
+	wg.Add(1)
+	go wait(Bulk)
+	assert.Eventually(t, func() bool { return len(r.order()) == 1 }, time.Second, time.Millisecond)
+
+	for _, p := range []Priority{Bulk, Background} {
+		wg.Add(1)
+		go wait(p)
+		assert.Eventually(t, func() bool { return s.Waiting()[p] == 1 }, time.Second, time.Millisecond)
+	}
+
+	// An Interactive request isn't queued behind the Bulk request holding the turn.
+	wg.Add(1)
+	go wait(Interactive)
+	assert.Eventually(t, func() bool { return len(r.order()) == 2 }, time.Second, time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		r.gate <- struct{}{}
+	}
+	wg.Wait()
+
+	assert.Equal(t, []Priority{Bulk, Interactive, Background, Bulk}, r.order())
+}
+
+func TestScheduler_Close(t *testing.T) {
+	g := make(gate)
+	s := NewScheduler(hclog.Default(), g, rate.Inf, 10, DefaultInteractiveReserve)
+
+	held := make(chan error)
+	go func() { held <- s.Wait(WithPriority(context.Background(), Bulk)) }()
+	assert.Eventually(t, func() bool { return s.Waiting()[Bulk] == 0 }, time.Second, time.Millisecond)
+
+	queued := make(chan error)
+	go func() { queued <- s.Wait(WithPriority(context.Background(), Background)) }()
+	assert.Eventually(t, func() bool { return s.Waiting()[Background] == 1 }, time.Second, time.Millisecond)
+
+	s.Close()
+	s.Close()
+
+	assert.ErrorIs(t, <-queued, ErrSchedulerClosed)
+	assert.ErrorIs(t, s.Wait(context.Background()), ErrSchedulerClosed)
+
+	// The request already handed its turn still gets its upstream token.
+	g <- struct{}{}
+	assert.NoError(t, <-held)
+}
+
+func TestScheduler_WaitCancelled(t *testing.T) {
+	g := make(gate)
+	s := NewScheduler(hclog.Default(), g, rate.Inf, 10, DefaultInteractiveReserve)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.NotNil(t, s.Wait(ctx))
+
+	// A cancelled request must not hold on to its turn.
+	go func() { g <- struct{}{} }()
+	assert.Nil(t, s.Wait(context.Background()))
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	assert.Equal(t, Interactive, PriorityFromContext(context.Background()))
+	assert.Equal(t, Bulk, PriorityFromContext(WithPriority(context.Background(), Bulk)))
+}
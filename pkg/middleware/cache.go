@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/utils"
 	"github.com/redis/go-redis/v9"
 	"net/http"
-	"os"
 	"time"
 )
 
@@ -76,22 +76,8 @@ func (c *Cache) Middleware(next http.Handler) http.Handler {
 }
 
 func UseCaching(l hclog.Logger) *Cache {
-	opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
-	if err != nil {
-		panic(err)
-	}
-
-	client := redis.NewClient(opt)
-	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
-	defer cancel()
-
-	if _, err := client.Ping(ctx).Result(); err != nil {
-		l.Error("Failed to connect to cache", "error", err)
-		panic(err)
-	}
-
 	return &Cache{
 		l:      l,
-		client: client,
+		client: utils.NewRedisClient(l),
 	}
 }
@@ -2,13 +2,20 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 )
 
 var CharacterContextKey = "character"
 
+// characterName matches a lower-cased character name, which is only ever letters.
+var characterName = regexp.MustCompile(`^[\p{Ll}\p{Lo}]+$`)
+
 type Character struct{}
 
 func (c *Character) Middleware(next http.Handler) http.Handler {
@@ -27,6 +34,11 @@ func (c *Character) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if !characterName.MatchString(character) {
+			http.Error(w, "character name must only be letters", http.StatusBadRequest)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), CharacterContextKey, character)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -35,3 +47,29 @@ func (c *Character) Middleware(next http.Handler) http.Handler {
 func UseCharacter() *Character {
 	return &Character{}
 }
+
+// ValidateCharacter lower-cases & validates a character given other than by the route, e.g. in a request body, the
+// same as the Region, Realm & Character middlewares would.
+func ValidateCharacter(region, realm, name string) (string, string, string, error) {
+	region = strings.ToLower(region)
+	realm = strings.ToLower(realm)
+	name = strings.ToLower(name)
+
+	if !slices.Contains(Regions, region) {
+		return "", "", "", fmt.Errorf("region '%s' is not a supported region", region)
+	}
+	if realm == "" {
+		return "", "", "", errors.New("realm must be provided")
+	}
+	if !realmSlug.MatchString(realm) {
+		return "", "", "", errors.New("realm must be a realm slug")
+	}
+	if len(name) < 2 || len(name) > 12 {
+		return "", "", "", errors.New("name must be between 2-12 characters")
+	}
+	if !characterName.MatchString(name) {
+		return "", "", "", errors.New("name must only be letters")
+	}
+
+	return region, realm, name, nil
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateCharacter(t *testing.T) {
+	tests := []struct {
+		name      string
+		character [3]string
+		want      [3]string
+		wantErr   string
+	}{
+		{name: "lower-cased", character: [3]string{"US", "Illidan", "Skxyz"}, want: [3]string{"us", "illidan", "skxyz"}},
+		{name: "bad region", character: [3]string{"cn", "illidan", "skxyz"}, wantErr: "region 'cn' is not a supported region"},
+		{name: "no realm", character: [3]string{"us", "", "skxyz"}, wantErr: "realm must be provided"},
+		{name: "realm slug", character: [3]string{"eu", "Aggra-Português", "skxyz"}, want: [3]string{"eu", "aggra-português", "skxyz"}},
+		{name: "realm with an apostrophe", character: [3]string{"us", "kel'thuzad", "skxyz"}, want: [3]string{"us", "kel'thuzad", "skxyz"}},
+		{name: "cyrillic realm", character: [3]string{"eu", "Гордунни", "Аня"}, want: [3]string{"eu", "гордунни", "аня"}},
+		{name: "realm with a path", character: [3]string{"us", "x/../../data/wow/token/index", "skxyz"}, wantErr: "realm must be a realm slug"},
+		{name: "realm with a query", character: [3]string{"us", "illidan?namespace=static-us", "skxyz"}, wantErr: "realm must be a realm slug"},
+		{name: "realm with a fragment", character: [3]string{"us", "illidan#", "skxyz"}, wantErr: "realm must be a realm slug"},
+		{name: "realm with a dot", character: [3]string{"us", "..", "skxyz"}, wantErr: "realm must be a realm slug"},
+		{name: "name with a path", character: [3]string{"us", "illidan", "x/../../y"}, wantErr: "name must only be letters"},
+		{name: "short name", character: [3]string{"us", "illidan", "s"}, wantErr: "name must be between 2-12 characters"},
+		{name: "long name", character: [3]string{"us", "illidan", "skxyzskxyzskx"}, wantErr: "name must be between 2-12 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, realm, name, err := ValidateCharacter(tt.character[0], tt.character[1], tt.character[2])
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, [3]string{region, realm, name})
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+var GuildContextKey = "guild"
+
+type Guild struct{}
+
+func (g *Guild) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		guild, ok := vars[GuildContextKey]
+		if !ok {
+			http.Error(w, "guild not provided in route parameter", http.StatusBadRequest)
+			return
+		}
+
+		guild = strings.TrimSpace(guild)
+
+		if len(guild) < 2 || len(guild) > 24 {
+			http.Error(w, "guild name must be between 2-24 characters", http.StatusBadRequest)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), GuildContextKey, guild)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func UseGuild() *Guild {
+	return &Guild{}
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps the per-window request counters used by the RateLimit middleware.
+type RateLimitStore interface {
+	// Increment adds a request for the key to the window containing now, returning the count for the current and
+	// previous windows.
+	Increment(ctx context.Context, key string, window time.Duration, now time.Time) (current, previous int64, err error)
+}
+
+// RateLimitKeyFunc derives the key a request is limited by.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimit is a middleware handler limiting the requests per client using a sliding window counter.
+type RateLimit struct {
+	l hclog.Logger
+
+	store  RateLimitStore
+	keyFn  RateLimitKeyFunc
+	limit  int
+	window time.Duration
+}
+
+func (rl *RateLimit) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := rl.keyFn(r)
+		now := time.Now()
+
+		current, previous, err := rl.store.Increment(r.Context(), key, rl.window, now)
+		if err != nil {
+			// Fail open, an unavailable store shouldn't take the API down with it.
+			rl.l.Error("RateLimitStore.Increment failed", "key", key, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		elapsed := time.Duration(now.UnixNano() % int64(rl.window))
+		count := slidingCount(current, previous, elapsed, rl.window)
+
+		remaining := rl.limit - int(math.Ceil(count))
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(rl.limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", fmt.Sprintf("%.0f", math.Ceil((rl.window-elapsed).Seconds())))
+
+		if count > float64(rl.limit) {
+			retry := retryAfter(current, previous, elapsed, rl.window, rl.limit)
+			w.Header().Set("Retry-After", fmt.Sprintf("%.0f", math.Ceil(retry.Seconds())))
+
+			rl.l.Warn("Rate-Limit exceeded", "key", key, "count", count, "limit", rl.limit)
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// slidingCount weights the previous window by how much of it still overlaps the sliding window.
+func slidingCount(current, previous int64, elapsed, window time.Duration) float64 {
+	weight := 1 - float64(elapsed)/float64(window)
+	return float64(previous)*weight + float64(current)
+}
+
+// retryAfter estimates how long until the sliding count falls back to within the limit.
+func retryAfter(current, previous int64, elapsed, window time.Duration, limit int) time.Duration {
+	// The previous window decaying is enough to get back under the limit.
+	if current <= int64(limit) && previous > 0 {
+		excess := slidingCount(current, previous, elapsed, window) - float64(limit)
+		return time.Duration(excess / float64(previous) * float64(window))
+	}
+
+	// Otherwise, wait for the current window to become the previous one and decay.
+	untilNext := window - elapsed
+	decay := 1 - float64(limit)/float64(current)
+	return untilNext + time.Duration(decay*float64(window))
+}
+
+// RemoteAddrKey limits requests by the client IP.
+func RemoteAddrKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// APIKeyOrRemoteAddrKey limits requests by the X-API-Key header when it's one of the keys, falling back to the client
+// IP. Any other key is ignored, so a client can't sidestep its limit by sending a new key with every request.
+func APIKeyOrRemoteAddrKey(keys []string) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		if key := r.Header.Get("X-API-Key"); key != "" && slices.Contains(keys, key) {
+			return "key:" + key
+		}
+
+		return RemoteAddrKey(r)
+	}
+}
+
+// apiKeys are the comma separated keys of API_KEYS, which are limited separately from their client's IP.
+func apiKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(os.Getenv("API_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// UseRateLimiting constructs a new RateLimit middleware handler allowing limit requests per window for each client.
+func UseRateLimiting(l hclog.Logger, store RateLimitStore, limit int, window time.Duration) *RateLimit {
+	return &RateLimit{
+		l:      l,
+		store:  store,
+		keyFn:  APIKeyOrRemoteAddrKey(apiKeys()),
+		limit:  limit,
+		window: window,
+	}
+}
+
+type memoryWindow struct {
+	index    int64
+	current  int64
+	previous int64
+}
+
+// MemoryRateLimitStore is a RateLimitStore local to the running process.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+}
+
+// Increment adds a request for the key to the window containing now.
+func (m *MemoryRateLimitStore) Increment(_ context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := now.UnixNano() / int64(window)
+	m.sweep(index, window, now)
+
+	w, ok := m.windows[key]
+	if !ok {
+		w = &memoryWindow{index: index}
+		m.windows[key] = w
+	}
+
+	switch {
+	case w.index == index-1:
+		w.previous, w.current = w.current, 0
+	case w.index < index-1:
+		w.previous, w.current = 0, 0
+	}
+	w.index = index
+	w.current++
+
+	return w.current, w.previous, nil
+}
+
+// sweep removes the windows no longer contributing to any count, at most once per window.
+func (m *MemoryRateLimitStore) sweep(index int64, window time.Duration, now time.Time) {
+	if now.Sub(m.lastSweep) < window {
+		return
+	}
+	m.lastSweep = now
+
+	for key, w := range m.windows {
+		if w.index < index-1 {
+			delete(m.windows, key)
+		}
+	}
+}
+
+// NewMemoryRateLimitStore creates an empty MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		windows: map[string]*memoryWindow{},
+	}
+}
+
+// RedisRateLimitStore is a RateLimitStore shared by every instance connected to the same redis.
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+// Increment adds a request for the key to the window containing now.
+func (s *RedisRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	index := now.UnixNano() / int64(window)
+	currentKey := fmt.Sprintf("ratelimit:%s:%d", key, index)
+	previousKey := fmt.Sprintf("ratelimit:%s:%d", key, index-1)
+
+	pipe := s.client.Pipeline()
+	incr := pipe.Incr(ctx, currentKey)
+	pipe.Expire(ctx, currentKey, 2*window)
+	prev := pipe.Get(ctx, previousKey)
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+
+	previous, err := prev.Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+
+	return incr.Val(), previous, nil
+}
+
+// NewRedisRateLimitStore creates a RedisRateLimitStore connected to REDIS_URL.
+func NewRedisRateLimitStore(l hclog.Logger) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: utils.NewRedisClient(l),
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStore_Increment(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	start := time.Unix(0, 0)
+
+	current, previous, _ := store.Increment(context.Background(), "ip:127.0.0.1", time.Minute, start)
+	assert.Equal(t, int64(1), current)
+	assert.Equal(t, int64(0), previous)
+
+	current, _, _ = store.Increment(context.Background(), "ip:127.0.0.1", time.Minute, start.Add(time.Second))
+	assert.Equal(t, int64(2), current)
+
+	// The next window carries the count as the previous window.
+	current, previous, _ = store.Increment(context.Background(), "ip:127.0.0.1", time.Minute, start.Add(time.Minute))
+	assert.Equal(t, int64(1), current)
+	assert.Equal(t, int64(2), previous)
+
+	// Skipping a window drops both counts.
+	current, previous, _ = store.Increment(context.Background(), "ip:127.0.0.1", time.Minute, start.Add(3*time.Minute))
+	assert.Equal(t, int64(1), current)
+	assert.Equal(t, int64(0), previous)
+}
+
+func TestRateLimit_Middleware(t *testing.T) {
+	t.Setenv("API_KEYS", "abc, def")
+
+	rl := UseRateLimiting(hclog.Default(), NewMemoryRateLimitStore(), 2, time.Hour)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		apiKey string
+		want   int
+	}{
+		{name: "first request", want: http.StatusOK},
+		{name: "second request", want: http.StatusOK},
+		{name: "third request exceeds limit", want: http.StatusTooManyRequests},
+		{name: "api key is limited separately", apiKey: "abc", want: http.StatusOK},
+		{name: "unknown api key is limited by ip", apiKey: "random", want: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Truef(t, rr.Code == tt.want, "got status %v, wanted %v", rr.Code, tt.want)
+			assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+
+			if tt.want == http.StatusTooManyRequests {
+				assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+				assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+			}
+		})
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"github.com/gorilla/mux"
 	"net/http"
+	"regexp"
 	"strings"
 )
 
 var RealmContextKey = "realm"
 
+// realmSlug matches a lower-cased realm slug, letters of any alphabet, digits, hyphens & apostrophes, so a realm can't
+// reach beyond its place in the path of an upstream request.
+var realmSlug = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}'-]+$`)
+
 type Realm struct{}
 
 func (r *Realm) Middleware(next http.Handler) http.Handler {
@@ -22,6 +27,11 @@ func (r *Realm) Middleware(next http.Handler) http.Handler {
 
 		realm = strings.ToLower(realm)
 
+		if !realmSlug.MatchString(realm) {
+			http.Error(w, "realm must be a realm slug", http.StatusBadRequest)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), RealmContextKey, realm)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -9,7 +9,8 @@ import (
 	"strings"
 )
 
-var regions = []string{"us", "eu", "kr", "tw"}
+// Regions are the regions supported by the API.
+var Regions = []string{"us", "eu", "kr", "tw"}
 
 var RegionContextKey = "region"
 
@@ -26,7 +27,7 @@ func (r *Region) Middleware(next http.Handler) http.Handler {
 
 		region = strings.ToLower(region)
 
-		if !slices.Contains(regions, region) {
+		if !slices.Contains(Regions, region) {
 			http.Error(w, fmt.Sprintf("region '%s' is not a supported region", region), http.StatusBadRequest)
 			return
 		}
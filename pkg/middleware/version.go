@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultAPIVersion is the version requests are served with when they don't ask for one. It's fixed to the original
+	// shapes so clients that never asked for a version aren't broken by a newer one.
+	DefaultAPIVersion = 1
+	// LatestAPIVersion is the newest version, advertised in the LatestAPIVersionHeader for clients to opt in to.
+	LatestAPIVersion = 2
+	// APIVersionHeader is the header a version may be requested with, and is answered with.
+	APIVersionHeader = "API-Version"
+	// LatestAPIVersionHeader is the header the LatestAPIVersion is advertised with.
+	LatestAPIVersionHeader = "API-Latest-Version"
+)
+
+// SupportedAPIVersions are the versions the API can respond with.
+//
+//   - 1: the original response shapes, e.g. the raw WarcraftLogs GraphQL structs.
+//   - 2: the DTO response shapes.
+var SupportedAPIVersions = []int{1, 2}
+
+var APIVersionContextKey = "api-version"
+
+// vendorMediaType matches the vendor media type a version may be requested with, e.g. application/vnd.amashan.v2+json
+var vendorMediaType = regexp.MustCompile(`^application/vnd\.amashan\.v(\d+)\+json$`)
+
+// Versioning is a middleware handler negotiating the version of the response shapes.
+type Versioning struct{}
+
+func (v *Versioning) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, err := requestedVersion(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !slices.Contains(SupportedAPIVersions, version) {
+			http.Error(w, fmt.Sprintf("api version '%d' is not supported", version), http.StatusNotAcceptable)
+			return
+		}
+
+		w.Header().Set(APIVersionHeader, strconv.Itoa(version))
+		w.Header().Set(LatestAPIVersionHeader, strconv.Itoa(LatestAPIVersion))
+		w.Header().Add("Vary", "Accept, "+APIVersionHeader)
+
+		ctx := context.WithValue(r.Context(), APIVersionContextKey, version)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestedVersion reads the version from the API-Version header, falling back to a vendor media type in the Accept
+// header, and then to the DefaultAPIVersion.
+func requestedVersion(r *http.Request) (int, error) {
+	if h := r.Header.Get(APIVersionHeader); h != "" {
+		version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(h), "v"))
+		if err != nil {
+			return 0, fmt.Errorf("header '%s' must be an integer", APIVersionHeader)
+		}
+		return version, nil
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		if m := vendorMediaType.FindStringSubmatch(mediaType); m != nil {
+			return strconv.Atoi(m[1])
+		}
+	}
+
+	return DefaultAPIVersion, nil
+}
+
+// APIVersionFromContext returns the negotiated version for the context, defaulting to the DefaultAPIVersion.
+func APIVersionFromContext(ctx context.Context) int {
+	if version, ok := ctx.Value(APIVersionContextKey).(int); ok {
+		return version
+	}
+
+	return DefaultAPIVersion
+}
+
+// UseVersioning constructs a new Versioning middleware handler.
+func UseVersioning() *Versioning {
+	return &Versioning{}
+}
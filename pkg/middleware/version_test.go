@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestVersioning_Middleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		accept   string
+		wantCode int
+		want     int
+	}{
+		{name: "defaults to the original shapes", wantCode: http.StatusOK, want: DefaultAPIVersion},
+		{name: "api-version header", header: "1", wantCode: http.StatusOK, want: 1},
+		{name: "api-version header with prefix", header: "v1", wantCode: http.StatusOK, want: 1},
+		{name: "vendor media type", accept: "text/html, application/vnd.amashan.v1+json; q=0.9", wantCode: http.StatusOK, want: 1},
+		{name: "plain json accept", accept: "application/json", wantCode: http.StatusOK, want: DefaultAPIVersion},
+		{name: "latest vendor media type", accept: "application/vnd.amashan.v2+json", wantCode: http.StatusOK, want: LatestAPIVersion},
+		{name: "header wins over accept", header: "2", accept: "application/vnd.amashan.v1+json", wantCode: http.StatusOK, want: 2},
+		{name: "unsupported version", header: "99", wantCode: http.StatusNotAcceptable},
+		{name: "malformed version", header: "latest", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			handler := UseVersioning().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = APIVersionFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/warcraftlogs/us/illidan/skxyz/parses", nil)
+			if tt.header != "" {
+				req.Header.Set(APIVersionHeader, tt.header)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, strconv.Itoa(tt.want), rr.Header().Get(APIVersionHeader))
+				assert.Equal(t, strconv.Itoa(LatestAPIVersion), rr.Header().Get(LatestAPIVersionHeader))
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/limiter"
 	"golang.org/x/time/rate"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -21,30 +24,28 @@ type URLFunc func() string
 // RaiderIOClient wraps the RaiderIO API
 type RaiderIOClient struct {
 	l                hclog.Logger
-	perMinuteLimiter *rate.Limiter
+	perMinuteLimiter limiter.Limiter
+	scheduler        *limiter.Scheduler
 	apiURLFn         URLFunc
 }
 
-// CharacterProfile gets a character's mythic plus statistics.
+// CharacterProfile gets a character's profile with their mythic plus statistics & the fields of the options.
 func (r *RaiderIOClient) CharacterProfile(ctx context.Context, options *CharacterProfileOptions) (*CharacterProfileResponse, error) {
 	const endpoint = "/characters/profile"
-	const fields = "mythic_plus_ranks,mythic_plus_recent_runs,mythic_plus_best_runs,mythic_plus_scores_by_season:current"
 
-	url := fmt.Sprintf("%s%s", r.apiURLFn(), endpoint)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	// The defaults are always requested, their fields are in every response. Fields requested twice are merged.
+	fields := append(slices.Clone(DefaultProfileFields), options.Fields...)
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"region": options.Region,
+		"realm":  options.Realm,
+		"name":   options.Character,
+		"fields": fieldsParam(fields),
+	})
 	if err != nil {
-		r.l.Error("Failed to create request", "url", url, "error", err)
 		return nil, err
 	}
 
-	// Set query params
-	q := req.URL.Query()
-	q.Add("region", options.Region)
-	q.Add("realm", options.Realm)
-	q.Add("name", options.Character)
-	q.Add("fields", fields)
-	req.URL.RawQuery = q.Encode()
-
 	res, err := r.Do(ctx, req)
 	if err != nil {
 		return nil, err
@@ -60,8 +61,244 @@ func (r *RaiderIOClient) CharacterProfile(ctx context.Context, options *Characte
 	return cpRes, nil
 }
 
+// GuildProfile gets a guild's profile with its raid progression, raid rankings & members.
+func (r *RaiderIOClient) GuildProfile(ctx context.Context, options *GuildProfileOptions) (*GuildProfileResponse, error) {
+	const endpoint = "/guilds/profile"
+	const fields = "raid_progression,raid_rankings,members"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"region": options.Region,
+		"realm":  options.Realm,
+		"name":   options.Guild,
+		"fields": fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	gpRes := &GuildProfileResponse{}
+	err = json.NewDecoder(res.Body).Decode(gpRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return gpRes, nil
+}
+
+// GuildBossKill gets the roster & item levels of a guild's first kill of a boss.
+func (r *RaiderIOClient) GuildBossKill(ctx context.Context, options *GuildBossKillOptions) (*GuildBossKillResponse, error) {
+	const endpoint = "/guilds/boss-kill"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"region":     options.Region,
+		"realm":      options.Realm,
+		"guild":      options.Guild,
+		"raid":       options.Raid,
+		"boss":       options.Boss,
+		"difficulty": options.Difficulty,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	bkRes := &GuildBossKillResponse{}
+	err = json.NewDecoder(res.Body).Decode(bkRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return bkRes, nil
+}
+
+// RaidingProgression gets how many guilds have killed each boss of a raid, with the first guilds to kill them.
+func (r *RaiderIOClient) RaidingProgression(ctx context.Context, options *RaidingProgressionOptions) (*RaidingProgressionResponse, error) {
+	const endpoint = "/raiding/progression"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"raid":       options.Raid,
+		"difficulty": options.Difficulty,
+		"region":     options.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rpRes := &RaidingProgressionResponse{}
+	err = json.NewDecoder(res.Body).Decode(rpRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpRes, nil
+}
+
+// MythicPlusAffixes gets the current week's affixes of a region.
+func (r *RaiderIOClient) MythicPlusAffixes(ctx context.Context, options *AffixesOptions) (*AffixesResponse, error) {
+	const endpoint = "/mythic-plus/affixes"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"region": options.Region,
+		"locale": options.Locale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	aRes := &AffixesResponse{}
+	err = json.NewDecoder(res.Body).Decode(aRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return aRes, nil
+}
+
+// MythicPlusStaticData gets the seasons & dungeons of an expansion.
+func (r *RaiderIOClient) MythicPlusStaticData(ctx context.Context, options *StaticDataOptions) (*StaticDataResponse, error) {
+	const endpoint = "/mythic-plus/static-data"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"expansion_id": strconv.Itoa(options.ExpansionID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	sdRes := &StaticDataResponse{}
+	err = json.NewDecoder(res.Body).Decode(sdRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdRes, nil
+}
+
+// MythicPlusSeasonCutoffs gets the scores needed to be in the top percentiles of a region for a season.
+func (r *RaiderIOClient) MythicPlusSeasonCutoffs(ctx context.Context, options *SeasonCutoffsOptions) (*SeasonCutoffsResponse, error) {
+	const endpoint = "/mythic-plus/season-cutoffs"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"season": options.Season,
+		"region": options.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	scRes := &SeasonCutoffsResponse{}
+	err = json.NewDecoder(res.Body).Decode(scRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return scRes, nil
+}
+
+// MythicPlusRuns gets a page of the runs leaderboard of a season.
+func (r *RaiderIOClient) MythicPlusRuns(ctx context.Context, options *RunsOptions) (*RunsResponse, error) {
+	const endpoint = "/mythic-plus/runs"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"season":  options.Season,
+		"region":  options.Region,
+		"dungeon": options.Dungeon,
+		"affixes": options.Affixes,
+		"page":    strconv.Itoa(options.Page),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rRes := &RunsResponse{}
+	err = json.NewDecoder(res.Body).Decode(rRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return rRes, nil
+}
+
+// MythicPlusRunDetails gets a run with its roster, their specs & item levels.
+func (r *RaiderIOClient) MythicPlusRunDetails(ctx context.Context, options *RunDetailsOptions) (*RunDetailsResponse, error) {
+	const endpoint = "/mythic-plus/run-details"
+
+	req, err := r.prepareRequest(endpoint, map[string]string{
+		"season": options.Season,
+		"id":     strconv.Itoa(options.ID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rdRes := &RunDetailsResponse{}
+	err = json.NewDecoder(res.Body).Decode(rdRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return rdRes, nil
+}
+
+// Close stops scheduling requests, those still waiting on the rate-limits fail.
+func (r *RaiderIOClient) Close() {
+	r.scheduler.Close()
+}
+
 // Do handles making http requests ensuring they abide by the given rate-limits.
 func (r *RaiderIOClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// The caller's context bounds the request itself, not only the wait for our turn.
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
 	// If we create a new context, we need to defer it.
 	var cancel context.CancelFunc
 	if ctx == nil {
@@ -69,8 +306,8 @@ func (r *RaiderIOClient) Do(ctx context.Context, req *http.Request) (*http.Respo
 		defer cancel()
 	}
 
-	// Ensure we aren't exceeding the minute rate limit.
-	if err := r.perMinuteLimiter.Wait(ctx); err != nil {
+	// Ensure we aren't exceeding the minute rate limit, waiting our turn by priority.
+	if err := r.scheduler.Wait(ctx); err != nil {
 		return nil, err
 	}
 
@@ -84,7 +321,7 @@ func (r *RaiderIOClient) Do(ctx context.Context, req *http.Request) (*http.Respo
 
 	// If we got a 404, we should drain the remaining tokens.
 	if res.StatusCode == http.StatusTooManyRequests {
-		r.perMinuteLimiter.ReserveN(time.Now().Add(1*time.Minute), r.perMinuteLimiter.Burst())
+		r.perMinuteLimiter.Drain(1 * time.Minute)
 		r.l.Info("RaiderIO Rate-Limit reached, drained remaining tokens")
 	}
 
@@ -104,11 +341,33 @@ func (r *RaiderIOClient) Do(ctx context.Context, req *http.Request) (*http.Respo
 	return res, nil
 }
 
+// prepareRequest util wraps common http.NewRequest(...) for a GET of the endpoint with the given query params.
+func (r *RaiderIOClient) prepareRequest(endpoint string, params map[string]string) (*http.Request, error) {
+	url := fmt.Sprintf("%s%s", r.apiURLFn(), endpoint)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		r.l.Error("Failed to create request", "url", url, "error", err)
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	for k, v := range params {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	return req, nil
+}
+
 // NewRaiderIOClient creates a new default RaiderIOClient
 func NewRaiderIOClient(l hclog.Logger) *RaiderIOClient {
+	perMinuteLimiter := limiter.New(l, "rio:per-minute", rate.Every(1*time.Minute), 300)
+
 	return &RaiderIOClient{
 		l:                l,
-		perMinuteLimiter: rate.NewLimiter(rate.Every(1*time.Minute), 300),
+		perMinuteLimiter: perMinuteLimiter,
+		scheduler: limiter.NewScheduler(l, perMinuteLimiter,
+			rate.Every(1*time.Minute), 300, limiter.DefaultInteractiveReserve),
 		apiURLFn: func() string {
 			return API_URL
 		},
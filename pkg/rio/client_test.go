@@ -80,3 +80,160 @@ func TestRaiderIOClient_CharacterProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestRaiderIOClient_CharacterProfile_Fields(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.CharacterProfile(context.Background(), &CharacterProfileOptions{
+		Region:    "us",
+		Realm:     "illidan",
+		Character: "skkzr",
+		Fields: []ProfileField{
+			FieldGear,
+			FieldTalents,
+			FieldGuild,
+			FieldRaidProgression,
+			FieldRaidAchievementCurve.With("amirdrassil-the-dreams-hope"),
+			FieldMythicPlusScoresBySeason.With(SeasonCurrent),
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Skkzr", got.Name)
+	assert.Equal(t, 528.4, got.Gear.ItemLevelEquipped)
+	assert.Equal(t, "31", got.Gear.Items["head"].Tier)
+	assert.Equal(t, 261, got.TalentLoadout.SpecID)
+	assert.Equal(t, &CharacterGuild{Name: "Heckin", Realm: "Illidan"}, got.Guild)
+	assert.Equal(t, "9/9 H", got.RaidProgression["amirdrassil-the-dreams-hope"].Summary)
+	assert.NotNil(t, got.RaidAchievementCurve[0].AheadOfTheCurve)
+	assert.Nil(t, got.RaidAchievementCurve[0].CuttingEdge)
+	assert.NotEmpty(t, got.MythicPlusScoresBySeason)
+
+	// Fields that weren't requested aren't responded with, besides the defaults.
+	assert.Nil(t, got.Covenant)
+	assert.NotEmpty(t, got.MythicPlusRanks)
+	assert.NotEmpty(t, got.MythicPlusBestRuns)
+}
+
+func TestRaiderIOClient_GuildProfile(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.GuildProfile(context.Background(), &GuildProfileOptions{
+		Region: "us",
+		Realm:  "illidan",
+		Guild:  "heckin",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Heckin", got.Name)
+	assert.Equal(t, 3, got.RaidProgression["nerubar-palace"].MythicBossesKilled)
+	assert.Equal(t, 2841, got.RaidRankings["nerubar-palace"].Mythic.World)
+	assert.Len(t, got.Members, 2)
+	assert.Equal(t, "Skkzr", got.Members[0].Character.Name)
+}
+
+func TestRaiderIOClient_GuildBossKill(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.GuildBossKill(context.Background(), &GuildBossKillOptions{
+		Region:     "us",
+		Realm:      "illidan",
+		Guild:      "heckin",
+		Raid:       "nerubar-palace",
+		Boss:       "ulgrax-the-devourer",
+		Difficulty: "mythic",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, got.Kill.IsSuccess)
+	assert.Equal(t, uint64(392514), got.Kill.DurationMS)
+	assert.Len(t, got.Roster, 2)
+	assert.Equal(t, "rogue", got.Roster[0].Character.Class.Slug)
+}
+
+func TestRaiderIOClient_RaidingProgression(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.RaidingProgression(context.Background(), &RaidingProgressionOptions{
+		Raid:       "nerubar-palace",
+		Difficulty: "mythic",
+		Region:     "world",
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, got.Progression, 2)
+	assert.Equal(t, 8, got.Progression[1].Progress)
+	assert.Equal(t, "Liquid", got.Progression[1].Guilds[0].Guild.Name)
+}
+
+func TestRaiderIOClient_MythicPlusAffixes(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.MythicPlusAffixes(context.Background(), &AffixesOptions{Region: "us", Locale: "en"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "us", got.Region)
+	assert.Len(t, got.AffixDetails, 3)
+	assert.Equal(t, "Tyrannical", got.AffixDetails[2].Name)
+}
+
+func TestRaiderIOClient_MythicPlusStaticData(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.MythicPlusStaticData(context.Background(), &StaticDataOptions{ExpansionID: 10})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "season-tww-1", got.Seasons[0].Slug)
+	assert.NotNil(t, got.Seasons[0].Starts["us"])
+	assert.Nil(t, got.Seasons[0].Ends["us"])
+	assert.Equal(t, 503, got.Seasons[0].Dungeons[0].ChallengeModeID)
+	assert.Len(t, got.Dungeons, 2)
+}
+
+func TestRaiderIOClient_MythicPlusSeasonCutoffs(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.MythicPlusSeasonCutoffs(context.Background(), &SeasonCutoffsOptions{Season: "season-tww-1", Region: "us"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3275.5, got.Cutoffs.P999.All.QuantileMinValue)
+	assert.Nil(t, got.Cutoffs.P750)
+}
+
+func TestRaiderIOClient_MythicPlusRuns(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.MythicPlusRuns(context.Background(), &RunsOptions{
+		Season:  "season-tww-1",
+		Region:  "world",
+		Dungeon: "all",
+		Affixes: "current",
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, got.Rankings, 2)
+	assert.Equal(t, 1, got.Rankings[0].Rank)
+	assert.Equal(t, 14, got.Rankings[0].Run.MythicLevel)
+	assert.Len(t, got.Rankings[0].Run.Roster, 5)
+}
+
+func TestRaiderIOClient_MythicPlusRunDetails(t *testing.T) {
+	r, srv := newMockedClient()
+	defer srv.Close()
+
+	got, err := r.MythicPlusRunDetails(context.Background(), &RunDetailsOptions{Season: "season-tww-1", ID: 4218877})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4218877, got.KeystoneRunID)
+	assert.Equal(t, "tank", got.Roster[0].Role)
+	assert.Equal(t, "Vengeance", got.Roster[0].Character.Spec.Name)
+	assert.Equal(t, 626.4, got.Roster[0].Items.ItemLevelEquipped)
+}
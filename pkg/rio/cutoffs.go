@@ -0,0 +1,54 @@
+package rio
+
+import "math"
+
+// CutoffDistance is how far a score is from the cutoff of a percentile.
+type CutoffDistance struct {
+	Percentile string `json:"percentile"`
+	// Top is the percentage of the region the percentile is, e.g. 0.1 for the top 0.1%.
+	Top       float64 `json:"top"`
+	Cutoff    float64 `json:"cutoff"`
+	Remaining float64 `json:"remaining"`
+	Reached   bool    `json:"reached"`
+}
+
+// Distances works out how far the score is from the cutoff of every known percentile, the highest percentile first.
+func (c *SeasonCutoffs) Distances(score float64) []CutoffDistance {
+	percentiles := []struct {
+		name   string
+		top    float64
+		cutoff *Cutoff
+	}{
+		{"p999", 0.1, c.P999},
+		{"p990", 1, c.P990},
+		{"p900", 10, c.P900},
+		{"p750", 25, c.P750},
+		{"p600", 40, c.P600},
+	}
+
+	distances := make([]CutoffDistance, 0, len(percentiles))
+	for _, p := range percentiles {
+		// Cutoffs aren't known until enough of the region has a score.
+		if p.cutoff == nil || p.cutoff.All.QuantileMinValue == 0 {
+			continue
+		}
+
+		cutoff := p.cutoff.All.QuantileMinValue
+		distances = append(distances, CutoffDistance{
+			Percentile: p.name,
+			Top:        p.top,
+			Cutoff:     cutoff,
+			Remaining:  math.Round(math.Max(cutoff-score, 0)*10) / 10,
+			Reached:    score >= cutoff,
+		})
+	}
+
+	return distances
+}
+
+// SeasonCutoffsDTO is the cutoffs of a season, with how far a score is from each of them when one is given.
+type SeasonCutoffsDTO struct {
+	SeasonCutoffsResponse
+	Score     *float64         `json:"score,omitempty"`
+	Distances []CutoffDistance `json:"distances,omitempty"`
+}
@@ -0,0 +1,22 @@
+package rio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeasonCutoffs_Distances(t *testing.T) {
+	cutoffs := &SeasonCutoffs{
+		P999: &Cutoff{All: CutoffQuantile{QuantileMinValue: 3275.5}},
+		P990: &Cutoff{All: CutoffQuantile{QuantileMinValue: 2911.4}},
+		// Not enough scores for the p900 cutoff yet.
+		P900: &Cutoff{},
+	}
+
+	assert.Equal(t, []CutoffDistance{
+		{Percentile: "p999", Top: 0.1, Cutoff: 3275.5, Remaining: 275.2},
+		{Percentile: "p990", Top: 1, Cutoff: 2911.4, Reached: true},
+	}, cutoffs.Distances(3000.3))
+
+	assert.Empty(t, (&SeasonCutoffs{}).Distances(3000))
+}
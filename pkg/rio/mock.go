@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/heckin-dev/amashan/test"
 	"net/http"
+	"strings"
 )
 
 type RaiderIOMock struct{}
@@ -22,19 +23,111 @@ func (i *RaiderIOMock) CharacterProfile(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	res := &CharacterProfileResponse{}
-	err := json.NewDecoder(bytes.NewReader(test.MPlusAllInOne)).Decode(res)
+	res := map[string]json.RawMessage{}
+	err := json.NewDecoder(bytes.NewReader(test.MPlusAllInOne)).Decode(&res)
 	if err != nil {
 		http.Error(w, "failed to decode test.MPlusAllInOne", http.StatusInternalServerError)
 		return
 	}
 
+	extra := map[string]json.RawMessage{}
+	err = json.NewDecoder(bytes.NewReader(test.ProfileFields)).Decode(&extra)
+	if err != nil {
+		http.Error(w, "failed to decode test.ProfileFields", http.StatusInternalServerError)
+		return
+	}
+
+	// Only respond with the requested fields, like RaiderIO.
+	requested := map[string]bool{}
+	for _, f := range strings.Split(q.Get("fields"), ",") {
+		field, _ := ProfileField(f).split()
+		requested[profileFieldKey(field)] = true
+	}
+
+	for _, field := range ProfileFields {
+		key := profileFieldKey(field)
+		if !requested[key] {
+			delete(res, key)
+		} else if v, ok := extra[key]; ok {
+			res[key] = v
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(res)
 }
 
+func (i *RaiderIOMock) GuildProfile(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"region", "realm", "name", "fields"}, test.GuildProfile, &GuildProfileResponse{})
+}
+
+func (i *RaiderIOMock) GuildBossKill(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"region", "realm", "guild", "raid", "boss", "difficulty"}, test.GuildBossKill, &GuildBossKillResponse{})
+}
+
+func (i *RaiderIOMock) RaidingProgression(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"raid", "difficulty", "region"}, test.RaidingProgression, &RaidingProgressionResponse{})
+}
+
+func (i *RaiderIOMock) MythicPlusAffixes(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"region", "locale"}, test.MPlusAffixes, &AffixesResponse{})
+}
+
+func (i *RaiderIOMock) MythicPlusStaticData(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"expansion_id"}, test.MPlusStaticData, &StaticDataResponse{})
+}
+
+func (i *RaiderIOMock) MythicPlusSeasonCutoffs(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"season", "region"}, test.MPlusSeasonCutoffs, &SeasonCutoffsResponse{})
+}
+
+func (i *RaiderIOMock) MythicPlusRuns(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"season", "region", "dungeon", "affixes", "page"}, test.MPlusRuns, &RunsResponse{})
+}
+
+func (i *RaiderIOMock) MythicPlusRunDetails(w http.ResponseWriter, r *http.Request) {
+	i.respond(w, r, []string{"season", "id"}, test.MPlusRunDetails, &RunDetailsResponse{})
+}
+
+// respond checks the request has the expected params, responding with the fixture decoded into res.
+func (i *RaiderIOMock) respond(w http.ResponseWriter, r *http.Request, expectedParams []string, fixture []byte, res any) {
+	q := r.URL.Query()
+	for _, expParam := range expectedParams {
+		if !q.Has(expParam) {
+			http.Error(w, fmt.Errorf("expected param: '%s' was missing", expParam).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := json.NewDecoder(bytes.NewReader(fixture)).Decode(res)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode fixture into %T", res), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// profileFieldKey is the key a ProfileField is responded with.
+func profileFieldKey(field ProfileField) string {
+	if field == FieldTalents {
+		return "talentLoadout"
+	}
+
+	return string(field)
+}
+
 func (i *RaiderIOMock) Route(r *mux.Router) {
 	r.HandleFunc("/characters/profile", i.CharacterProfile)
+	r.HandleFunc("/guilds/profile", i.GuildProfile)
+	r.HandleFunc("/guilds/boss-kill", i.GuildBossKill)
+	r.HandleFunc("/raiding/progression", i.RaidingProgression)
+	r.HandleFunc("/mythic-plus/affixes", i.MythicPlusAffixes)
+	r.HandleFunc("/mythic-plus/static-data", i.MythicPlusStaticData)
+	r.HandleFunc("/mythic-plus/season-cutoffs", i.MythicPlusSeasonCutoffs)
+	r.HandleFunc("/mythic-plus/runs", i.MythicPlusRuns)
+	r.HandleFunc("/mythic-plus/run-details", i.MythicPlusRunDetails)
 }
 
 func NewRaiderIOMock() *RaiderIOMock {
@@ -2,13 +2,18 @@ package rio
 
 import (
 	"context"
+	"fmt"
 	"github.com/heckin-dev/amashan/pkg/middleware"
+	"slices"
+	"strings"
 )
 
 type CharacterProfileOptions struct {
 	Region    string
 	Realm     string
 	Character string
+	// Fields are the fields requested alongside the character's info & the DefaultProfileFields.
+	Fields []ProfileField
 }
 
 // CharacterProfileOptionsFromContext creates a CharacterProfileOptions from a given context.
@@ -22,3 +27,210 @@ func CharacterProfileOptionsFromContext(ctx context.Context) *CharacterProfileOp
 		Character: ctx.Value(middleware.CharacterContextKey).(string),
 	}
 }
+
+type GuildProfileOptions struct {
+	Region string
+	Realm  string
+	Guild  string
+}
+
+// GuildProfileOptionsFromContext creates a GuildProfileOptions from a given context.
+//
+// It is expected that the context contains the middleware.RegionContextKey, middleware.RealmContextKey &
+// middleware.GuildContextKey
+func GuildProfileOptionsFromContext(ctx context.Context) *GuildProfileOptions {
+	return &GuildProfileOptions{
+		Region: ctx.Value(middleware.RegionContextKey).(string),
+		Realm:  ctx.Value(middleware.RealmContextKey).(string),
+		Guild:  ctx.Value(middleware.GuildContextKey).(string),
+	}
+}
+
+type GuildBossKillOptions struct {
+	Region     string
+	Realm      string
+	Guild      string
+	Raid       string
+	Boss       string
+	Difficulty string
+}
+
+type RaidingProgressionOptions struct {
+	Raid       string
+	Difficulty string
+	// Region is a region, or world.
+	Region string
+}
+
+type AffixesOptions struct {
+	Region string
+	// Locale is the language of the affix names & descriptions, e.g. en.
+	Locale string
+}
+
+// LatestExpansionID is the ID RaiderIO knows the latest expansion by, The War Within.
+const LatestExpansionID = 10
+
+type StaticDataOptions struct {
+	ExpansionID int
+}
+
+type SeasonCutoffsOptions struct {
+	// Season is the slug of the season, e.g. season-tww-1.
+	Season string
+	Region string
+}
+
+type RunsOptions struct {
+	// Season is the slug of the season, e.g. season-tww-1.
+	Season string
+	// Region is a region, or world.
+	Region string
+	// Dungeon is the slug of a dungeon, or all.
+	Dungeon string
+	// Affixes are the slugs of the affixes joined by dashes, current or all.
+	Affixes string
+	// Page is the page of the leaderboard, starting from 0.
+	Page int
+}
+
+type RunDetailsOptions struct {
+	Season string
+	ID     int
+}
+
+// Difficulties are the raid difficulties RaiderIO ranks.
+var Difficulties = []string{"normal", "heroic", "mythic"}
+
+// ProfileField is a field a CharacterProfile can be requested with. Some fields take arguments after them, see With.
+type ProfileField string
+
+const (
+	FieldGear                                     ProfileField = "gear"
+	FieldTalents                                  ProfileField = "talents"
+	FieldGuild                                    ProfileField = "guild"
+	FieldCovenant                                 ProfileField = "covenant"
+	FieldRaidProgression                          ProfileField = "raid_progression"
+	FieldRaidAchievementCurve                     ProfileField = "raid_achievement_curve"
+	FieldMythicPlusScoresBySeason                 ProfileField = "mythic_plus_scores_by_season"
+	FieldMythicPlusRanks                          ProfileField = "mythic_plus_ranks"
+	FieldPreviousMythicPlusRanks                  ProfileField = "previous_mythic_plus_ranks"
+	FieldMythicPlusRecentRuns                     ProfileField = "mythic_plus_recent_runs"
+	FieldMythicPlusBestRuns                       ProfileField = "mythic_plus_best_runs"
+	FieldMythicPlusAlternateRuns                  ProfileField = "mythic_plus_alternate_runs"
+	FieldMythicPlusHighestLevelRuns               ProfileField = "mythic_plus_highest_level_runs"
+	FieldMythicPlusWeeklyHighestLevelRuns         ProfileField = "mythic_plus_weekly_highest_level_runs"
+	FieldMythicPlusPreviousWeeklyHighestLevelRuns ProfileField = "mythic_plus_previous_weekly_highest_level_runs"
+)
+
+const (
+	// SeasonCurrent is the argument of FieldMythicPlusScoresBySeason for the current season's scores.
+	SeasonCurrent = "current"
+	// SeasonPrevious is the argument of FieldMythicPlusScoresBySeason for the previous season's scores.
+	SeasonPrevious = "previous"
+)
+
+// ProfileFields are every supported ProfileField.
+var ProfileFields = []ProfileField{
+	FieldGear,
+	FieldTalents,
+	FieldGuild,
+	FieldCovenant,
+	FieldRaidProgression,
+	FieldRaidAchievementCurve,
+	FieldMythicPlusScoresBySeason,
+	FieldMythicPlusRanks,
+	FieldPreviousMythicPlusRanks,
+	FieldMythicPlusRecentRuns,
+	FieldMythicPlusBestRuns,
+	FieldMythicPlusAlternateRuns,
+	FieldMythicPlusHighestLevelRuns,
+	FieldMythicPlusWeeklyHighestLevelRuns,
+	FieldMythicPlusPreviousWeeklyHighestLevelRuns,
+}
+
+// fieldsWithArgs are the fields that must be given arguments, e.g. the raids of raid_achievement_curve.
+var fieldsWithArgs = map[ProfileField]bool{
+	FieldRaidAchievementCurve:     true,
+	FieldMythicPlusScoresBySeason: true,
+}
+
+// DefaultProfileFields are the fields a CharacterProfile is always requested with.
+var DefaultProfileFields = []ProfileField{
+	FieldMythicPlusRanks,
+	FieldMythicPlusRecentRuns,
+	FieldMythicPlusBestRuns,
+	FieldMythicPlusScoresBySeason.With(SeasonCurrent),
+}
+
+// With returns the field with the arguments RaiderIO expects after it, e.g. mythic_plus_scores_by_season:current.
+func (f ProfileField) With(args ...string) ProfileField {
+	if len(args) == 0 {
+		return f
+	}
+
+	return ProfileField(string(f) + ":" + strings.Join(args, ":"))
+}
+
+// split returns the field without its arguments, and the arguments.
+func (f ProfileField) split() (ProfileField, []string) {
+	parts := strings.Split(string(f), ":")
+	return ProfileField(parts[0]), parts[1:]
+}
+
+// ParseProfileFields parses a comma separated list of fields, e.g. gear,raid_achievement_curve:nerubar-palace
+func ParseProfileFields(s string) ([]ProfileField, error) {
+	var fields []ProfileField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		field, args := ProfileField(part).split()
+		if !slices.Contains(ProfileFields, field) {
+			return nil, fmt.Errorf("unknown field '%s'", field)
+		}
+
+		if slices.Contains(args, "") {
+			return nil, fmt.Errorf("field '%s' has an empty argument", field)
+		}
+
+		if fieldsWithArgs[field] && len(args) == 0 {
+			return nil, fmt.Errorf("field '%s' requires arguments, e.g. '%s:<arg>'", field, field)
+		}
+		if !fieldsWithArgs[field] && len(args) > 0 {
+			return nil, fmt.Errorf("field '%s' doesn't take arguments", field)
+		}
+
+		fields = append(fields, ProfileField(part))
+	}
+
+	return fields, nil
+}
+
+// fieldsParam joins the fields into the fields query param, merging the arguments of any repeated field.
+func fieldsParam(fields []ProfileField) string {
+	var order []ProfileField
+	args := map[ProfileField][]string{}
+	for _, f := range fields {
+		field, fArgs := f.split()
+		if _, ok := args[field]; !ok {
+			order = append(order, field)
+			args[field] = []string{}
+		}
+
+		for _, arg := range fArgs {
+			if !slices.Contains(args[field], arg) {
+				args[field] = append(args[field], arg)
+			}
+		}
+	}
+
+	params := make([]string, 0, len(order))
+	for _, field := range order {
+		params = append(params, string(field.With(args[field]...)))
+	}
+
+	return strings.Join(params, ",")
+}
@@ -0,0 +1,62 @@
+package rio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+)
+
+func TestParseProfileFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		want    []ProfileField
+		wantErr bool
+	}{
+		{name: "empty", fields: "", want: nil},
+		{name: "fields", fields: "gear, Talents,,guild", want: []ProfileField{FieldGear, FieldTalents, FieldGuild}},
+		{
+			name:   "arguments",
+			fields: "mythic_plus_scores_by_season:current:previous,raid_achievement_curve:nerubar-palace",
+			want: []ProfileField{
+				FieldMythicPlusScoresBySeason.With(SeasonCurrent, SeasonPrevious),
+				FieldRaidAchievementCurve.With("nerubar-palace"),
+			},
+		},
+		{name: "unknown field", fields: "gear,mounts", wantErr: true},
+		{name: "missing arguments", fields: "raid_achievement_curve", wantErr: true},
+		{name: "empty argument", fields: "mythic_plus_scores_by_season:current:", wantErr: true},
+		{name: "unexpected arguments", fields: "gear:head", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfileFields(tt.fields)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_fieldsParam(t *testing.T) {
+	assert.Equal(t,
+		"mythic_plus_ranks,mythic_plus_recent_runs,mythic_plus_best_runs,mythic_plus_scores_by_season:current",
+		fieldsParam(DefaultProfileFields))
+
+	// Repeated fields have their arguments merged.
+	assert.Equal(t, "mythic_plus_scores_by_season:current:previous,gear", fieldsParam([]ProfileField{
+		FieldMythicPlusScoresBySeason.With(SeasonCurrent),
+		FieldGear,
+		FieldMythicPlusScoresBySeason.With(SeasonPrevious, SeasonCurrent),
+	}))
+
+	// Requested alongside the defaults, nothing is asked for twice.
+	assert.Equal(t,
+		"mythic_plus_ranks,mythic_plus_recent_runs,mythic_plus_best_runs,mythic_plus_scores_by_season:current:previous,gear",
+		fieldsParam(append(slices.Clone(DefaultProfileFields), FieldGear, FieldMythicPlusRanks, FieldMythicPlusScoresBySeason.With(SeasonPrevious))))
+}
@@ -1,13 +1,32 @@
 package rio
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
 
+// CharacterProfileResponse is a character's profile, only the requested ProfileField(s) are set. The fields of the
+// DefaultProfileFields are always responded with, the rest only when they're set.
 type CharacterProfileResponse struct {
 	CharacterInfo
-	MythicPlusBestRuns       []MythicPlusRun         `json:"mythic_plus_best_runs"`
-	MythicPlusRecentRuns     []MythicPlusRun         `json:"mythic_plus_recent_runs"`
-	MythicPlusScoresBySeason []MythicPlusSeasonScore `json:"mythic_plus_scores_by_season"`
-	MythicPlusRanks          MythicPlusRanks         `json:"mythic_plus_ranks"`
+	MythicPlusBestRuns                       []MythicPlusRun            `json:"mythic_plus_best_runs"`
+	MythicPlusRecentRuns                     []MythicPlusRun            `json:"mythic_plus_recent_runs"`
+	MythicPlusAlternateRuns                  []MythicPlusRun            `json:"mythic_plus_alternate_runs,omitempty"`
+	MythicPlusHighestLevelRuns               []MythicPlusRun            `json:"mythic_plus_highest_level_runs,omitempty"`
+	MythicPlusWeeklyHighestLevelRuns         []MythicPlusRun            `json:"mythic_plus_weekly_highest_level_runs,omitempty"`
+	MythicPlusPreviousWeeklyHighestLevelRuns []MythicPlusRun            `json:"mythic_plus_previous_weekly_highest_level_runs,omitempty"`
+	MythicPlusScoresBySeason                 []MythicPlusSeasonScore    `json:"mythic_plus_scores_by_season"`
+	MythicPlusRanks                          MythicPlusRanks            `json:"mythic_plus_ranks"`
+	PreviousMythicPlusRanks                  *MythicPlusRanks           `json:"previous_mythic_plus_ranks,omitempty"`
+	Gear                                     *Gear                      `json:"gear,omitempty"`
+	TalentLoadout                            *TalentLoadout             `json:"talentLoadout,omitempty"`
+	Guild                                    *CharacterGuild            `json:"guild,omitempty"`
+	Covenant                                 *Covenant                  `json:"covenant,omitempty"`
+	RaidProgression                          map[string]RaidProgression `json:"raid_progression,omitempty"`
+	RaidAchievementCurve                     []RaidAchievementCurve     `json:"raid_achievement_curve,omitempty"`
 }
 
 type CharacterInfo struct {
@@ -39,11 +58,33 @@ type MythicPlusRun struct {
 	URL                 string            `json:"url"`
 }
 
+// RunDetailsOptions are the options to get the details of the run, which RaiderIO only identifies in its URL, e.g.
+// https://raider.io/mythic-plus-runs/season-df-4/11777827-8-halls-of-infusion
+func (m *MythicPlusRun) RunDetailsOptions() (*RunDetailsOptions, error) {
+	u, err := url.Parse(m.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "mythic-plus-runs" {
+		return nil, fmt.Errorf("url '%s' is not a mythic plus run", m.URL)
+	}
+
+	id, err := strconv.Atoi(strings.SplitN(parts[2], "-", 2)[0])
+	if err != nil {
+		return nil, fmt.Errorf("url '%s' has no run id", m.URL)
+	}
+
+	return &RunDetailsOptions{Season: parts[1], ID: id}, nil
+}
+
 type MythicPlusAffix struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Icon        string `json:"icon"`
+	IconURL     string `json:"icon_url,omitempty"`
 	WowheadURL  string `json:"wowhead_url"`
 }
 
@@ -92,3 +133,268 @@ type WorldRegionAndRealm struct {
 	Region int `json:"region"`
 	Realm  int `json:"realm"`
 }
+
+type Gear struct {
+	UpdatedAt         time.Time           `json:"updated_at"`
+	ItemLevelEquipped float64             `json:"item_level_equipped"`
+	ItemLevelTotal    float64             `json:"item_level_total"`
+	Items             map[string]GearItem `json:"items"`
+}
+
+type GearItem struct {
+	ItemID      int    `json:"item_id"`
+	ItemLevel   int    `json:"item_level"`
+	ItemQuality int    `json:"item_quality"`
+	Name        string `json:"name"`
+	Icon        string `json:"icon"`
+	Tier        string `json:"tier,omitempty"`
+	Enchant     int    `json:"enchant,omitempty"`
+	Gems        []int  `json:"gems"`
+	Bonuses     []int  `json:"bonuses"`
+}
+
+type TalentLoadout struct {
+	SpecID      int    `json:"loadout_spec_id"`
+	LoadoutText string `json:"loadout_text"`
+}
+
+type CharacterGuild struct {
+	Name  string `json:"name"`
+	Realm string `json:"realm"`
+}
+
+type Covenant struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	RenownLevel int    `json:"renown_level"`
+}
+
+type RaidProgression struct {
+	Summary            string `json:"summary"`
+	ExpansionID        int    `json:"expansion_id"`
+	TotalBosses        int    `json:"total_bosses"`
+	NormalBossesKilled int    `json:"normal_bosses_killed"`
+	HeroicBossesKilled int    `json:"heroic_bosses_killed"`
+	MythicBossesKilled int    `json:"mythic_bosses_killed"`
+}
+
+// RaidAchievementCurve is when a character earned Ahead of the Curve & Cutting Edge for a raid, if they have.
+type RaidAchievementCurve struct {
+	Raid            string     `json:"raid"`
+	AheadOfTheCurve *time.Time `json:"aotc,omitempty"`
+	CuttingEdge     *time.Time `json:"cutting_edge,omitempty"`
+}
+
+// GuildProfileResponse is a guild's profile with its raid progression, raid rankings & members.
+type GuildProfileResponse struct {
+	GuildInfo
+	RaidProgression map[string]RaidProgression `json:"raid_progression"`
+	RaidRankings    map[string]RaidRankings    `json:"raid_rankings"`
+	Members         []GuildMember              `json:"members"`
+}
+
+type GuildInfo struct {
+	Name          string    `json:"name"`
+	Faction       string    `json:"faction"`
+	Region        string    `json:"region"`
+	Realm         string    `json:"realm"`
+	LastCrawledAt time.Time `json:"last_crawled_at"`
+	ProfileURL    string    `json:"profile_url"`
+}
+
+// RaidRankings are a guild's ranks for a raid by difficulty, a rank of 0 being unranked.
+type RaidRankings struct {
+	Normal WorldRegionAndRealm `json:"normal"`
+	Heroic WorldRegionAndRealm `json:"heroic"`
+	Mythic WorldRegionAndRealm `json:"mythic"`
+}
+
+type GuildMember struct {
+	Rank      int           `json:"rank"`
+	Character CharacterInfo `json:"character"`
+}
+
+// GuildBossKillResponse is a guild's first kill of a boss, with the roster that killed it.
+type GuildBossKillResponse struct {
+	Kill   *BossKill        `json:"kill"`
+	Roster []BossKillMember `json:"roster"`
+}
+
+type BossKill struct {
+	DefeatedAt           time.Time `json:"defeatedAt"`
+	DurationMS           uint64    `json:"durationMs"`
+	IsSuccess            bool      `json:"isSuccess"`
+	ItemLevelEquippedAvg float64   `json:"itemLevelEquippedAvg"`
+	ItemLevelEquippedMax float64   `json:"itemLevelEquippedMax"`
+	ItemLevelEquippedMin float64   `json:"itemLevelEquippedMin"`
+}
+
+type BossKillMember struct {
+	Character         BossKillCharacter `json:"character"`
+	ItemLevelEquipped float64           `json:"itemLevelEquipped"`
+}
+
+type BossKillCharacter struct {
+	ID    int       `json:"id"`
+	Name  string    `json:"name"`
+	Class NamedSlug `json:"class"`
+	Spec  NamedSlug `json:"spec"`
+	Race  NamedSlug `json:"race"`
+}
+
+// NamedSlug is the id, name & slug RaiderIO describes classes, specs, races, realms & regions with.
+type NamedSlug struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// RaidingProgressionResponse is how far guilds have progressed through a raid, one entry per bosses killed.
+type RaidingProgressionResponse struct {
+	Progression []BossProgression `json:"progression"`
+}
+
+type BossProgression struct {
+	Progress    int                `json:"progress"`
+	TotalGuilds int                `json:"totalGuilds"`
+	Guilds      []ProgressionGuild `json:"guilds"`
+}
+
+type ProgressionGuild struct {
+	Guild      RankedGuild `json:"guild"`
+	DefeatedAt time.Time   `json:"defeatedAt"`
+}
+
+type RankedGuild struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Faction string    `json:"faction"`
+	Realm   NamedSlug `json:"realm"`
+	Region  NamedSlug `json:"region"`
+	Path    string    `json:"path"`
+}
+
+// AffixesResponse is the current week's affixes of a region.
+type AffixesResponse struct {
+	Region         string            `json:"region"`
+	Title          string            `json:"title"`
+	LeaderboardURL string            `json:"leaderboard_url"`
+	AffixDetails   []MythicPlusAffix `json:"affix_details"`
+}
+
+// StaticDataResponse is the seasons & dungeons of an expansion.
+type StaticDataResponse struct {
+	Seasons  []StaticSeason  `json:"seasons"`
+	Dungeons []StaticDungeon `json:"dungeons"`
+}
+
+type StaticSeason struct {
+	Slug          string           `json:"slug"`
+	Name          string           `json:"name"`
+	ShortName     string           `json:"short_name"`
+	SeasonalAffix *MythicPlusAffix `json:"seasonal_affix"`
+	// Starts & Ends are keyed by region, being nil until RaiderIO knows them.
+	Starts   map[string]*time.Time `json:"starts"`
+	Ends     map[string]*time.Time `json:"ends"`
+	Dungeons []StaticDungeon       `json:"dungeons"`
+}
+
+type StaticDungeon struct {
+	ID              int    `json:"id"`
+	ChallengeModeID int    `json:"challenge_mode_id"`
+	Slug            string `json:"slug"`
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+}
+
+// SeasonCutoffsResponse is the scores needed to be in the top percentiles of a region for a season.
+type SeasonCutoffsResponse struct {
+	Cutoffs SeasonCutoffs `json:"cutoffs"`
+}
+
+type SeasonCutoffs struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	// P999 is the top 0.1%, who earn the season's title.
+	P999 *Cutoff `json:"p999"`
+	P990 *Cutoff `json:"p990"`
+	P900 *Cutoff `json:"p900"`
+	P750 *Cutoff `json:"p750"`
+	P600 *Cutoff `json:"p600"`
+}
+
+// Cutoff is a percentile's cutoff for everyone, and by faction.
+type Cutoff struct {
+	All      CutoffQuantile `json:"all"`
+	Horde    CutoffQuantile `json:"horde"`
+	Alliance CutoffQuantile `json:"alliance"`
+}
+
+type CutoffQuantile struct {
+	QuantileMinValue           float64 `json:"quantileMinValue"`
+	QuantilePopulationCount    int     `json:"quantilePopulationCount"`
+	QuantilePopulationFraction float64 `json:"quantilePopulationFraction"`
+	TotalPopulationCount       int     `json:"totalPopulationCount"`
+}
+
+// RunsResponse is a page of a season's runs leaderboard.
+type RunsResponse struct {
+	Rankings       []RankedRun `json:"rankings"`
+	LeaderboardURL string      `json:"leaderboard_url"`
+}
+
+type RankedRun struct {
+	Rank  int            `json:"rank"`
+	Score float64        `json:"score"`
+	Run   LeaderboardRun `json:"run"`
+}
+
+// LeaderboardRun is a run with its roster, as RaiderIO ranks it.
+type LeaderboardRun struct {
+	Season          string            `json:"season"`
+	Status          string            `json:"status,omitempty"`
+	Dungeon         RunDungeon        `json:"dungeon"`
+	KeystoneRunID   int               `json:"keystone_run_id"`
+	MythicLevel     int               `json:"mythic_level"`
+	ClearTimeMS     uint64            `json:"clear_time_ms"`
+	KeystoneTimeMS  uint64            `json:"keystone_time_ms"`
+	CompletedAt     time.Time         `json:"completed_at"`
+	NumChests       int               `json:"num_chests"`
+	TimeRemainingMS int64             `json:"time_remaining_ms"`
+	WeeklyModifiers []MythicPlusAffix `json:"weekly_modifiers"`
+	Roster          []RunMember       `json:"roster"`
+}
+
+// RunDetailsResponse is a single run with its roster, their specs & item levels.
+type RunDetailsResponse struct {
+	LeaderboardRun
+}
+
+type RunDungeon struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	Slug            string `json:"slug"`
+	ExpansionID     int    `json:"expansion_id"`
+	KeystoneTimerMS uint64 `json:"keystone_timer_ms"`
+}
+
+type RunMember struct {
+	Character RunCharacter    `json:"character"`
+	Role      string          `json:"role"`
+	Items     *RunMemberItems `json:"items,omitempty"`
+}
+
+type RunCharacter struct {
+	ID     int       `json:"id"`
+	Name   string    `json:"name"`
+	Class  NamedSlug `json:"class"`
+	Race   NamedSlug `json:"race"`
+	Spec   NamedSlug `json:"spec"`
+	Realm  NamedSlug `json:"realm"`
+	Region NamedSlug `json:"region"`
+}
+
+type RunMemberItems struct {
+	ItemLevelEquipped float64 `json:"item_level_equipped"`
+	ItemLevelTotal    float64 `json:"item_level_total"`
+}
@@ -0,0 +1,36 @@
+package rio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMythicPlusRun_RunDetailsOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    *RunDetailsOptions
+		wantErr bool
+	}{
+		{
+			name: "run",
+			url:  "https://raider.io/mythic-plus-runs/season-df-4/11777827-8-halls-of-infusion",
+			want: &RunDetailsOptions{Season: "season-df-4", ID: 11777827},
+		},
+		{name: "not a run", url: "https://raider.io/characters/us/illidan/Skkzr", wantErr: true},
+		{name: "no id", url: "https://raider.io/mythic-plus-runs/season-df-4/halls-of-infusion", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&MythicPlusRun{URL: tt.url}).RunDetailsOptions()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"time"
+)
+
+// NewRedisClient connects to the redis instance at REDIS_URL, panicking if it cannot be reached.
+func NewRedisClient(l hclog.Logger) *redis.Client {
+	opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(opt)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+
+	if _, err := client.Ping(ctx).Result(); err != nil {
+		l.Error("Failed to connect to redis", "error", err)
+		panic(err)
+	}
+
+	return client
+}
@@ -0,0 +1,246 @@
+package wl
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hasura/go-graphql-client"
+	"sort"
+	"strings"
+)
+
+// MaxCharacterBatch is the most characters looked up by a single batched query.
+const MaxCharacterBatch = 20
+
+// MaxEventWindowBatch is the most event windows gotten by a single batched query.
+const MaxEventWindowBatch = 10
+
+// BatchCharacter identifies a character looked up in a CharacterBatch.
+type BatchCharacter struct {
+	Name         string `json:"name"`
+	ServerSlug   string `json:"realm"`
+	ServerRegion string `json:"region"`
+}
+
+// CharacterBatch aliases the characterData.character lookups of many characters into a single query, sharing the
+// ranking filters of the CharacterParsesQueryOptions between them.
+type CharacterBatch struct {
+	characters []BatchCharacter
+	options    *CharacterParsesQueryOptions
+}
+
+// alias is the name a character's lookup is aliased to in the query.
+func (b *CharacterBatch) alias(i int) string {
+	return fmt.Sprintf("c%d", i)
+}
+
+// Query builds the batched query & its variables.
+func (b *CharacterBatch) Query() (string, map[string]any, error) {
+	vars := b.options.rankingVars()
+	delete(vars, "name")
+	delete(vars, "server_slug")
+	delete(vars, "server_region")
+	vars["zone_id"] = graphql.Int(b.options.ZoneID)
+	vars["partition"] = nullableInt(b.options.Partition)
+
+	var characters strings.Builder
+	for i, c := range b.characters {
+		alias := b.alias(i)
+		vars["name_"+alias] = graphql.String(c.Name)
+		vars["server_slug_"+alias] = graphql.String(c.ServerSlug)
+		vars["server_region_"+alias] = graphql.String(c.ServerRegion)
+
+		fmt.Fprintf(&characters, "%[1]s:character(name: $name_%[1]s, serverSlug: $server_slug_%[1]s, serverRegion: $server_region_%[1]s){hidden,zoneRankings(zoneID: $zone_id, partition: $partition, metric: $metric, difficulty: $difficulty, size: $size, role: $role, className: $class_name, specName: $spec_name, timeframe: $timeframe)},", alias)
+	}
+
+	defs, err := variableDefinitions(vars)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := fmt.Sprintf("query (%s){characterData{%s},rateLimitData{limitPerHour,pointsSpentThisHour,pointsResetIn}}",
+		defs, strings.TrimSuffix(characters.String(), ","))
+
+	return query, vars, nil
+}
+
+// Decode reads the response of the batched query into a CharacterParsesQuery per character, in the order they were
+// added. Characters WarcraftLogs doesn't know have a nil Character.
+func (b *CharacterBatch) Decode(data []byte) (*BatchCharacterParsesQuery, error) {
+	var resp struct {
+		CharacterData map[string]*struct {
+			Hidden       *bool        `json:"hidden"`
+			ZoneRankings *ZoneRanking `json:"zoneRankings"`
+		} `json:"characterData"`
+		RateLimitData struct {
+			LimitPerHour        int     `json:"limitPerHour"`
+			PointsSpentThisHour float64 `json:"pointsSpentThisHour"`
+			PointsResetIn       int     `json:"pointsResetIn"`
+		} `json:"rateLimitData"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	batch := &BatchCharacterParsesQuery{
+		RateLimitData: RateLimitData{
+			LimitPerHour:        graphql.Int(resp.RateLimitData.LimitPerHour),
+			PointsSpentThisHour: graphql.Float(resp.RateLimitData.PointsSpentThisHour),
+			PointsResetIn:       graphql.Int(resp.RateLimitData.PointsResetIn),
+		},
+	}
+
+	for i := range b.characters {
+		cpq := &CharacterParsesQuery{RateLimitData: batch.RateLimitData}
+		if c := resp.CharacterData[b.alias(i)]; c != nil {
+			cpq.CharacterData.Character = &Character{ZoneRankings: c.ZoneRankings}
+			if c.Hidden != nil {
+				hidden := graphql.Boolean(*c.Hidden)
+				cpq.CharacterData.Character.Hidden = &hidden
+			}
+		}
+		batch.Characters = append(batch.Characters, cpq)
+	}
+
+	return batch, nil
+}
+
+// EventWindowBatch aliases the report.events of many windows of a report into a single query, each window being the
+// first page of events of a single fight between its start & end time.
+type EventWindowBatch struct {
+	code     string
+	dataType EventDataType
+	windows  []ReportEventWindow
+}
+
+// alias is the name a window's events are aliased to in the query.
+func (b *EventWindowBatch) alias(i int) string {
+	return fmt.Sprintf("w%d", i)
+}
+
+// Query builds the batched query & its variables.
+func (b *EventWindowBatch) Query() (string, map[string]any, error) {
+	vars := map[string]any{
+		"code":      graphql.String(b.code),
+		"data_type": b.dataType,
+		"limit":     graphql.Int(eventPageLimit),
+	}
+
+	var windows strings.Builder
+	for i, window := range b.windows {
+		alias := b.alias(i)
+		vars["fight_ids_"+alias] = []graphql.Int{graphql.Int(window.FightID)}
+		vars["start_time_"+alias] = graphql.Float(window.StartTime)
+		vars["end_time_"+alias] = graphql.Float(window.EndTime)
+
+		fmt.Fprintf(&windows, "%[1]s:events(dataType: $data_type, fightIDs: $fight_ids_%[1]s, startTime: $start_time_%[1]s, endTime: $end_time_%[1]s, limit: $limit){data,nextPageTimestamp},", alias)
+	}
+
+	defs, err := variableDefinitions(vars)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := fmt.Sprintf("query (%s){reportData{report(code: $code){%s}},rateLimitData{limitPerHour,pointsSpentThisHour,pointsResetIn}}",
+		defs, strings.TrimSuffix(windows.String(), ","))
+
+	return query, vars, nil
+}
+
+// Decode reads the response of the batched query into the first page of events of each window, in the order they
+// were added.
+func (b *EventWindowBatch) Decode(data []byte) (*BatchReportEventsQuery, error) {
+	var resp struct {
+		ReportData struct {
+			Report map[string]*ReportEventPaginator `json:"report"`
+		} `json:"reportData"`
+		RateLimitData struct {
+			LimitPerHour        int     `json:"limitPerHour"`
+			PointsSpentThisHour float64 `json:"pointsSpentThisHour"`
+			PointsResetIn       int     `json:"pointsResetIn"`
+		} `json:"rateLimitData"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	batch := &BatchReportEventsQuery{
+		RateLimitData: RateLimitData{
+			LimitPerHour:        graphql.Int(resp.RateLimitData.LimitPerHour),
+			PointsSpentThisHour: graphql.Float(resp.RateLimitData.PointsSpentThisHour),
+			PointsResetIn:       graphql.Int(resp.RateLimitData.PointsResetIn),
+		},
+	}
+
+	for i := range b.windows {
+		page := resp.ReportData.Report[b.alias(i)]
+		if page == nil {
+			return nil, ErrReportNotFound
+		}
+		batch.Windows = append(batch.Windows, page)
+	}
+
+	return batch, nil
+}
+
+// variableDefinitions declares the variables of a query, typed the same way the graphql client types them. A variable
+// of a type without a GraphQL type here is an error.
+func variableDefinitions(vars map[string]any) (string, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var defs strings.Builder
+	for _, name := range names {
+		var t string
+		switch vars[name].(type) {
+		case graphql.String:
+			t = "String!"
+		case *graphql.String:
+			t = "String"
+		case graphql.Int:
+			t = "Int!"
+		case *graphql.Int:
+			t = "Int"
+		case []graphql.Int:
+			t = "[Int]"
+		case graphql.Float:
+			t = "Float!"
+		case EventDataType:
+			t = "EventDataType!"
+		case *CharacterRankingMetricType:
+			t = "CharacterRankingMetricType"
+		case *RoleType:
+			t = "RoleType"
+		case *RankingTimeframeType:
+			t = "RankingTimeframeType"
+		default:
+			return "", fmt.Errorf("wl: no GraphQL type for variable '%s' of %T", name, vars[name])
+		}
+		fmt.Fprintf(&defs, "$%s:%s", name, t)
+	}
+
+	return defs.String(), nil
+}
+
+// NewCharacterBatch creates a CharacterBatch looking up the characters with the ranking filters of the options.
+func NewCharacterBatch(options *CharacterParsesQueryOptions, characters []BatchCharacter) *CharacterBatch {
+	return &CharacterBatch{
+		characters: characters,
+		options:    options,
+	}
+}
+
+// NewEventWindowBatch creates an EventWindowBatch getting the events of the data type within each window of the report.
+func NewEventWindowBatch(code string, dataType EventDataType, windows []ReportEventWindow) *EventWindowBatch {
+	return &EventWindowBatch{
+		code:     code,
+		dataType: dataType,
+		windows:  windows,
+	}
+}
@@ -0,0 +1,100 @@
+package wl
+
+import (
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCharacterBatch_Query(t *testing.T) {
+	metric := MetricDPS
+	batch := NewCharacterBatch(&CharacterParsesQueryOptions{ZoneID: 38, Partition: Int(1), Metric: &metric}, []BatchCharacter{
+		{Name: "skxyz", ServerSlug: "illidan", ServerRegion: "us"},
+		{Name: "aradin", ServerSlug: "draenor", ServerRegion: "eu"},
+	})
+
+	query, vars, err := batch.Query()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(query, "query ($class_name:String$difficulty:Int$metric:CharacterRankingMetricType$name_c0:String!$name_c1:String!$partition:Int$"))
+	assert.Contains(t, query, "c0:character(name: $name_c0, serverSlug: $server_slug_c0, serverRegion: $server_region_c0){hidden,zoneRankings(zoneID: $zone_id, partition: $partition, metric: $metric")
+	assert.Contains(t, query, "c1:character(name: $name_c1")
+	assert.True(t, strings.HasSuffix(query, "},rateLimitData{limitPerHour,pointsSpentThisHour,pointsResetIn}}"))
+
+	assert.EqualValues(t, "draenor", vars["server_slug_c1"])
+	assert.EqualValues(t, 38, vars["zone_id"])
+	assert.Equal(t, &metric, vars["metric"])
+	assert.NotContains(t, vars, "name")
+}
+
+func TestVariableDefinitions_UnknownType(t *testing.T) {
+	_, err := variableDefinitions(map[string]any{"code": graphql.String("abcd1234"), "fight": 2})
+	assert.EqualError(t, err, "wl: no GraphQL type for variable 'fight' of int")
+}
+
+func TestCharacterBatch_Decode(t *testing.T) {
+	batch := NewCharacterBatch(&CharacterParsesQueryOptions{ZoneID: 38, Partition: Int(1)}, []BatchCharacter{
+		{Name: "skxyz", ServerSlug: "illidan", ServerRegion: "us"},
+		{Name: "nobody", ServerSlug: "illidan", ServerRegion: "us"},
+	})
+
+	raw := `{"characterData": {
+		"c0": {"hidden": false, "zoneRankings": {"bestPerformanceAverage": 82.5, "rankings": [
+			{"encounter": {"id": 2902, "name": "Ulgrax the Devourer"}, "rankPercent": 95.1, "lockedIn": true, "totalKills": 6}
+		]}},
+		"c1": null
+	}, "rateLimitData": {"limitPerHour": 3600, "pointsSpentThisHour": 42.5, "pointsResetIn": 1200}}`
+
+	bcq, err := batch.Decode([]byte(raw))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 3600, bcq.Data().LimitPerHour)
+	assert.EqualValues(t, 42.5, bcq.Data().PointsSpentThisHour)
+	assert.Len(t, bcq.Characters, 2)
+
+	found := bcq.Characters[0].ToDTO()
+	assert.False(t, *found.Hidden)
+	assert.Equal(t, 95.1, *found.Summary.Bosses[0].Best)
+
+	assert.Nil(t, bcq.Characters[1].CharacterData.Character)
+	assert.Nil(t, bcq.Characters[1].ToDTO().Summary)
+}
+
+func TestEventWindowBatch_Query(t *testing.T) {
+	batch := NewEventWindowBatch("abcd1234", DamageTakenEvents, []ReportEventWindow{
+		{FightID: 2, StartTime: 20000, EndTime: 40000},
+		{FightID: 3, StartTime: 110000, EndTime: 120000},
+	})
+
+	query, vars, err := batch.Query()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(query, "query ($code:String!$data_type:EventDataType!$end_time_w0:Float!$end_time_w1:Float!$fight_ids_w0:[Int]$"))
+	assert.Contains(t, query, "w1:events(dataType: $data_type, fightIDs: $fight_ids_w1, startTime: $start_time_w1, endTime: $end_time_w1, limit: $limit){data,nextPageTimestamp}")
+	assert.True(t, strings.HasSuffix(query, "}},rateLimitData{limitPerHour,pointsSpentThisHour,pointsResetIn}}"))
+
+	assert.EqualValues(t, 110000, vars["start_time_w1"])
+	assert.Len(t, vars["fight_ids_w0"], 1)
+}
+
+func TestEventWindowBatch_Decode(t *testing.T) {
+	batch := NewEventWindowBatch("abcd1234", DamageTakenEvents, []ReportEventWindow{{FightID: 2}, {FightID: 3}})
+
+	raw := `{"reportData": {"report": {
+		"w0": {"data": [{"timestamp": 29000, "fight": 2, "targetID": 1, "abilityGameID": 100, "amount": 3000}], "nextPageTimestamp": null},
+		"w1": {"data": [], "nextPageTimestamp": 115000}
+	}}, "rateLimitData": {"limitPerHour": 3600, "pointsSpentThisHour": 42.5, "pointsResetIn": 1200}}`
+
+	beq, err := batch.Decode([]byte(raw))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 42.5, beq.Data().PointsSpentThisHour)
+	assert.Len(t, beq.Windows, 2)
+	assert.Equal(t, float64(3000), beq.Windows[0].Data[0].Amount)
+	assert.Nil(t, beq.Windows[0].NextPageTimestamp)
+	assert.EqualValues(t, 115000, *beq.Windows[1].NextPageTimestamp)
+
+	_, err = batch.Decode([]byte(`{"reportData": {"report": null}}`))
+	assert.ErrorIs(t, err, ErrReportNotFound)
+}
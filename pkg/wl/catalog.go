@@ -0,0 +1,125 @@
+package wl
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// mythicPlusZonePrefix is what the names of Mythic+ zones start with, e.g. Mythic+ Season 1.
+const mythicPlusZonePrefix = "Mythic+"
+
+// ExpansionCatalog is every PartitionedExpansion known to WarcraftLogs, with their zones indexed by ID. It is never
+// modified once created.
+type ExpansionCatalog struct {
+	expansions []*PartitionedExpansion
+	zones      map[int]*ExpansionZone
+}
+
+// Expansions returns the expansions, latest first.
+func (c *ExpansionCatalog) Expansions() []*PartitionedExpansion {
+	return c.expansions
+}
+
+// Latest returns the latest expansion, or nil when there are none.
+func (c *ExpansionCatalog) Latest() *PartitionedExpansion {
+	if len(c.expansions) == 0 {
+		return nil
+	}
+
+	return c.expansions[0]
+}
+
+// Expansion returns the expansion for the ID.
+func (c *ExpansionCatalog) Expansion(id int) (*PartitionedExpansion, error) {
+	for _, e := range c.expansions {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+
+	return nil, ErrExpansionNotFound
+}
+
+// Zone returns the zone for the ID, from any expansion.
+func (c *ExpansionCatalog) Zone(id int) (*ExpansionZone, error) {
+	zone, ok := c.zones[id]
+	if !ok {
+		return nil, ErrZoneNotFound
+	}
+
+	return zone, nil
+}
+
+// CurrentRaid returns the latest raid still being ranked, the zone of the latest expansion that isn't frozen or a
+// Mythic+ season. An expansion without a raid yet falls back to the raid of the expansion before it.
+func (c *ExpansionCatalog) CurrentRaid() (*ExpansionZone, error) {
+	for _, e := range c.expansions {
+		var current *ExpansionZone
+		for i, z := range e.Zones {
+			if z.Frozen || strings.HasPrefix(z.Name, mythicPlusZonePrefix) {
+				continue
+			}
+			if current == nil || z.ID > current.ID {
+				current = &e.Zones[i]
+			}
+		}
+
+		if current != nil {
+			return current, nil
+		}
+	}
+
+	return nil, ErrZoneNotFound
+}
+
+// DefaultPartition returns the default partition of the zone, or nil when the zone isn't partitioned.
+func (c *ExpansionCatalog) DefaultPartition(zoneID int) (*int, error) {
+	zone, err := c.Zone(zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partition := range zone.Partitions {
+		if partition.Default {
+			return Int(partition.ID), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// zoneIDs returns the IDs of every zone, in ascending order.
+func (c *ExpansionCatalog) zoneIDs() []int {
+	ids := make([]int, 0, len(c.zones))
+	for id := range c.zones {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// NewExpansionCatalog creates an ExpansionCatalog from the expansions, sorting them latest first.
+func NewExpansionCatalog(expansions []*PartitionedExpansion) *ExpansionCatalog {
+	sorted := slices.Clone(expansions)
+	slices.SortFunc(sorted, func(a, b *PartitionedExpansion) int {
+		return -cmp.Compare(a.ID, b.ID)
+	})
+
+	c := &ExpansionCatalog{
+		expansions: sorted,
+		zones:      map[int]*ExpansionZone{},
+	}
+
+	for _, e := range sorted {
+		for i := range e.Zones {
+			// Zones are shared by expansions, e.g. Mythic+ Seasons. Keep the latest expansion's.
+			if _, ok := c.zones[e.Zones[i].ID]; !ok {
+				c.zones[e.Zones[i].ID] = &e.Zones[i]
+			}
+		}
+	}
+
+	return c
+}
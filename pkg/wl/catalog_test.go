@@ -0,0 +1,109 @@
+package wl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testCatalog() *ExpansionCatalog {
+	return NewExpansionCatalog([]*PartitionedExpansion{
+		{
+			ID:   5,
+			Name: "Dragonflight",
+			Zones: []ExpansionZone{
+				{ID: 35, Name: "Amirdrassil", Partitions: []ZonePartition{{ID: 1}, {ID: 4, Default: true}}},
+				{ID: 36, Name: "Mythic+ Season 4"},
+			},
+		},
+		{
+			ID:   6,
+			Name: "The War Within",
+			Zones: []ExpansionZone{
+				{ID: 38, Name: "Nerub-ar Palace", Partitions: []ZonePartition{{ID: 1, Default: true}}},
+			},
+		},
+	})
+}
+
+func TestExpansionCatalog(t *testing.T) {
+	c := testCatalog()
+
+	assert.Equal(t, 6, c.Latest().ID)
+	assert.Equal(t, []int{6, 5}, []int{c.Expansions()[0].ID, c.Expansions()[1].ID})
+
+	e, err := c.Expansion(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "Dragonflight", e.Name)
+
+	_, err = c.Expansion(1)
+	assert.ErrorIs(t, err, ErrExpansionNotFound)
+
+	z, err := c.Zone(35)
+	assert.NoError(t, err)
+	assert.Equal(t, "Amirdrassil", z.Name)
+}
+
+func TestExpansionCatalog_DefaultPartition(t *testing.T) {
+	c := testCatalog()
+
+	tests := []struct {
+		name    string
+		zoneID  int
+		want    *int
+		wantErr error
+	}{
+		{name: "latest expansion", zoneID: 38, want: Int(1)},
+		{name: "older expansion", zoneID: 35, want: Int(4)},
+		{name: "not partitioned", zoneID: 36, want: nil},
+		{name: "unknown zone", zoneID: 99, wantErr: ErrZoneNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.DefaultPartition(tt.zoneID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpansionCatalog_CurrentRaid(t *testing.T) {
+	c := NewExpansionCatalog([]*PartitionedExpansion{
+		{
+			ID: 6,
+			Zones: []ExpansionZone{
+				{ID: 38, Name: "Nerub-ar Palace", Frozen: true},
+				{ID: 39, Name: "Mythic+ Season 1"},
+				{ID: 42, Name: "Liberation of Undermine"},
+				{ID: 43, Name: "Mythic+ Season 2"},
+			},
+		},
+	})
+
+	zone, err := c.CurrentRaid()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, zone.ID)
+
+	// Without a raid in the latest expansion, the expansion before it has the current raid.
+	c = NewExpansionCatalog(append(c.Expansions(), &PartitionedExpansion{ID: 7, Zones: []ExpansionZone{{ID: 44, Name: "Mythic+ Season 3"}}}))
+	zone, err = c.CurrentRaid()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, zone.ID)
+
+	_, err = NewExpansionCatalog(nil).CurrentRaid()
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+}
+
+func TestExpansionCatalog_Empty(t *testing.T) {
+	c := NewExpansionCatalog(nil)
+
+	assert.Nil(t, c.Latest())
+
+	_, err := c.DefaultPartition(38)
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+}
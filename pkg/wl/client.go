@@ -1,32 +1,55 @@
 package wl
 
 import (
-	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hasura/go-graphql-client"
+	"github.com/heckin-dev/amashan/pkg/limiter"
 	"golang.org/x/oauth2/clientcredentials"
+	"math"
 	"net/http"
 	"os"
-	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	WL_API_URL = "https://www.warcraftlogs.com/api/v2/client"
+	WL_API_URL    = "https://www.warcraftlogs.com/api/v2/client"
+	WL_REPORT_URL = "https://www.warcraftlogs.com/reports"
+)
+
+const (
+	initialSetupBackoff = 5 * time.Second
+	maxSetupBackoff     = 5 * time.Minute
+)
+
+const (
+	// eventPageLimit is the most events of a single page.
+	eventPageLimit = 10000
+	// maxEventPages is the most pages of events followed before giving up.
+	maxEventPages = 10
 )
 
 // WarcraftLogsClient wraps the WarcraftLogs v2 GraphQL API abstracting requests we care about.
 type WarcraftLogsClient struct {
 	l hclog.Logger
 
-	config  *clientcredentials.Config
-	limiter *PointLimiter
+	config        *clientcredentials.Config
+	limiter       PointBudget
+	costs         *CostEstimator
+	waitForPoints bool
+	inFlight      atomic.Int32
+	ready         atomic.Bool
+	// seeded is set once a query has set the budget from WarcraftLogs, until then the points spent are our own guess.
+	seeded atomic.Bool
 
-	mu        sync.Mutex
-	expansion *PartitionedExpansion
+	// mu serialises loading & refreshing the catalog, reading it only needs the atomic.
+	mu      sync.Mutex
+	catalog atomic.Pointer[ExpansionCatalog]
 }
 
 // GetRateLimit returns the current rate limit remaining for the client.
@@ -40,49 +63,89 @@ func (w *WarcraftLogsClient) GetRateLimit(ctx context.Context) (*RateLimitQuery,
 	return rlq, nil
 }
 
-// GetExpansionEncounters gets the encounters for all the expansions.
-func (w *WarcraftLogsClient) GetExpansionEncounters(ctx context.Context) (*PartitionedExpansion, error) {
+// GetExpansionCatalog gets every expansion with their zones, encounters & partitions, keeping them until cleared or
+// refreshed.
+func (w *WarcraftLogsClient) GetExpansionCatalog(ctx context.Context) (*ExpansionCatalog, error) {
+	if catalog := w.catalog.Load(); catalog != nil {
+		return catalog, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Someone else may have loaded it while we waited.
+	if catalog := w.catalog.Load(); catalog != nil {
+		return catalog, nil
+	}
+
+	catalog, err := w.queryExpansionCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.catalog.Store(catalog)
+
+	return catalog, nil
+}
+
+// RefreshExpansionCatalog re-queries the expansions, swapping the new catalog in for the cached one and returning
+// what changed between them.
+func (w *WarcraftLogsClient) RefreshExpansionCatalog(ctx context.Context) (*CatalogDiff, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if w.expansion != nil {
-		return w.expansion, nil
+	catalog, err := w.queryExpansionCatalog(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	previous := w.catalog.Swap(catalog)
+	if previous == nil {
+		previous = NewExpansionCatalog(nil)
+	}
+
+	return DiffExpansionCatalogs(previous, catalog), nil
+}
+
+func (w *WarcraftLogsClient) queryExpansionCatalog(ctx context.Context) (*ExpansionCatalog, error) {
 	eeq := &ExpansionEncountersQuery{}
 	if err := w.Query(ctx, eeq, nil); err != nil {
 		w.l.Error("ExpansionEncountersQuery failed", "error", err)
 		return nil, err
 	}
 
-	// Sort the expansions and get the latest.
-	pe := PartitionedExpansionFromExpansionEncounterQuery(eeq)
-	slices.SortFunc(pe, func(a, b *PartitionedExpansion) int {
-		return -cmp.Compare(a.ID, b.ID)
-	})
-	w.expansion = pe[0]
+	return NewExpansionCatalog(PartitionedExpansionFromExpansionEncounterQuery(eeq)), nil
+}
 
-	return w.expansion, nil
+// GetExpansionEncounters gets the latest expansion.
+func (w *WarcraftLogsClient) GetExpansionEncounters(ctx context.Context) (*PartitionedExpansion, error) {
+	catalog, err := w.GetExpansionCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := catalog.Latest()
+	if latest == nil {
+		return nil, ErrExpansionNotFound
+	}
+
+	return latest, nil
 }
 
 // GetParsesForCharacter gets the parses for the given character, partition and zone id.
 func (w *WarcraftLogsClient) GetParsesForCharacter(ctx context.Context, options *CharacterParsesQueryOptions) (*CharacterParsesQuery, error) {
-	var partition int
-	if options.Partition == nil {
+	partition := options.Partition
+	if partition == nil {
 		// Get the default partition for the zone
-		partition = w.GetDefaultPartitionByZoneID(options.ZoneID)
-	} else {
-		partition = *options.Partition
+		var err error
+		if partition, err = w.GetDefaultPartitionByZoneID(ctx, options.ZoneID); err != nil {
+			return nil, err
+		}
 	}
 
 	cpq := &CharacterParsesQuery{}
-	vars := map[string]any{
-		"name":          graphql.String(options.Name),
-		"server_slug":   graphql.String(options.ServerSlug),
-		"server_region": graphql.String(options.ServerRegion),
-		"partition":     graphql.Int(partition),
-		"zone_id":       graphql.Int(options.ZoneID),
-	}
+	vars := options.rankingVars()
+	vars["partition"] = nullableInt(partition)
+	vars["zone_id"] = graphql.Int(options.ZoneID)
 	if err := w.Query(ctx, cpq, vars); err != nil {
 		w.l.Error("CharacterParsesQuery failed", "error", err)
 		return nil, err
@@ -90,20 +153,359 @@ func (w *WarcraftLogsClient) GetParsesForCharacter(ctx context.Context, options
 	return cpq, nil
 }
 
+// GetParsesForCharacters gets the parses of many characters with the same ranking filters, looking up to
+// MaxCharacterBatch characters per query. The queries are in the order of the characters, those WarcraftLogs doesn't
+// know have a nil Character.
+func (w *WarcraftLogsClient) GetParsesForCharacters(ctx context.Context, options *CharacterParsesQueryOptions, characters []BatchCharacter) ([]*CharacterParsesQuery, error) {
+	batchOptions := *options
+	if batchOptions.Partition == nil {
+		// Get the default partition for the zone
+		partition, err := w.GetDefaultPartitionByZoneID(ctx, options.ZoneID)
+		if err != nil {
+			return nil, err
+		}
+		batchOptions.Partition = partition
+	}
+
+	var parses []*CharacterParsesQuery
+	for start := 0; start < len(characters); start += MaxCharacterBatch {
+		batch := NewCharacterBatch(&batchOptions, characters[start:min(start+MaxCharacterBatch, len(characters))])
+		query, vars, err := batch.Query()
+		if err != nil {
+			w.l.Error("BatchCharacterParsesQuery failed", "error", err)
+			return nil, err
+		}
+
+		// Batches of different sizes cost different points, estimate them apart.
+		name := fmt.Sprintf("%s/%d", QueryName(&BatchCharacterParsesQuery{}), len(batch.characters))
+
+		bcq := &BatchCharacterParsesQuery{}
+		err = w.spend(ctx, name, bcq, func(ctx context.Context, client *graphql.Client) error {
+			data, err := client.ExecRaw(ctx, query, vars)
+			if err != nil {
+				return err
+			}
+
+			decoded, err := batch.Decode(data)
+			if err != nil {
+				return err
+			}
+			*bcq = *decoded
+
+			return nil
+		})
+		if err != nil {
+			w.l.Error("BatchCharacterParsesQuery failed", "error", err)
+			return nil, err
+		}
+
+		parses = append(parses, bcq.Characters...)
+	}
+
+	return parses, nil
+}
+
+// GetEncounterRankingsForCharacter gets the character's ranked fights of an encounter.
+func (w *WarcraftLogsClient) GetEncounterRankingsForCharacter(ctx context.Context, options *CharacterParsesQueryOptions, encounterID int) (*EncounterRankingsQuery, error) {
+	var partition *graphql.Int
+	if options.Partition != nil {
+		p := graphql.Int(*options.Partition)
+		partition = &p
+	}
+
+	erq := &EncounterRankingsQuery{}
+	vars := options.rankingVars()
+	vars["encounter_id"] = graphql.Int(encounterID)
+	vars["partition"] = partition
+	if err := w.Query(ctx, erq, vars); err != nil {
+		w.l.Error("EncounterRankingsQuery failed", "error", err)
+		return nil, err
+	}
+	return erq, nil
+}
+
+// GetEncounterCharacterRankings gets a page of the world's best characters on an encounter.
+func (w *WarcraftLogsClient) GetEncounterCharacterRankings(ctx context.Context, options *WorldRankingsOptions) (*WorldEncounterCharacterRankingsQuery, error) {
+	wcq := &WorldEncounterCharacterRankingsQuery{}
+	if err := w.Query(ctx, wcq, options.characterVars()); err != nil {
+		w.l.Error("WorldEncounterCharacterRankingsQuery failed", "error", err)
+		return nil, err
+	}
+
+	if wcq.WorldData.Encounter == nil {
+		return nil, ErrEncounterNotFound
+	}
+
+	return wcq, nil
+}
+
+// GetEncounterFightRankings gets a page of the world's best fights on an encounter.
+func (w *WarcraftLogsClient) GetEncounterFightRankings(ctx context.Context, options *WorldRankingsOptions) (*WorldEncounterFightRankingsQuery, error) {
+	wfq := &WorldEncounterFightRankingsQuery{}
+	if err := w.Query(ctx, wfq, options.fightVars()); err != nil {
+		w.l.Error("WorldEncounterFightRankingsQuery failed", "error", err)
+		return nil, err
+	}
+
+	if wfq.WorldData.Encounter == nil {
+		return nil, ErrEncounterNotFound
+	}
+
+	return wfq, nil
+}
+
+// GetReport gets the report for the given code, with its fights, players, zone and owner.
+func (w *WarcraftLogsClient) GetReport(ctx context.Context, code string) (*ReportQuery, error) {
+	rq := &ReportQuery{}
+	vars := map[string]any{
+		"code": graphql.String(code),
+	}
+	if err := w.Query(ctx, rq, vars); err != nil {
+		w.l.Error("ReportQuery failed", "error", err)
+		return nil, err
+	}
+
+	if rq.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	return rq, nil
+}
+
+// GetReportFight gets a single fight from the report for the given code, along with the players in it.
+func (w *WarcraftLogsClient) GetReportFight(ctx context.Context, code string, fightID int) (*ReportFightQuery, error) {
+	rfq := &ReportFightQuery{}
+	vars := map[string]any{
+		"code":      graphql.String(code),
+		"fight_ids": []graphql.Int{graphql.Int(fightID)},
+	}
+	if err := w.Query(ctx, rfq, vars); err != nil {
+		w.l.Error("ReportFightQuery failed", "error", err)
+		return nil, err
+	}
+
+	if rfq.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	if len(rfq.ReportData.Report.Fights) == 0 {
+		return nil, ErrFightNotFound
+	}
+
+	return rfq, nil
+}
+
+// GetReportTable gets the table of the given data type for the fights of a report.
+func (w *WarcraftLogsClient) GetReportTable(ctx context.Context, options *ReportDataOptions) (*ReportTableQuery, error) {
+	rtq := &ReportTableQuery{}
+	vars := options.vars()
+	vars["data_type"] = TableDataType(options.DataType)
+
+	if err := w.Query(ctx, rtq, vars); err != nil {
+		w.l.Error("ReportTableQuery failed", "error", err)
+		return nil, err
+	}
+
+	if rtq.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	return rtq, nil
+}
+
+// GetReportGraph gets the graph of the given data type for the fights of a report.
+func (w *WarcraftLogsClient) GetReportGraph(ctx context.Context, options *ReportDataOptions) (*ReportGraphQuery, error) {
+	rgq := &ReportGraphQuery{}
+	vars := options.vars()
+	vars["data_type"] = GraphDataType(options.DataType)
+
+	if err := w.Query(ctx, rgq, vars); err != nil {
+		w.l.Error("ReportGraphQuery failed", "error", err)
+		return nil, err
+	}
+
+	if rgq.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	return rgq, nil
+}
+
+// GetReportAbilities gets the abilities used in the report for the given code.
+func (w *WarcraftLogsClient) GetReportAbilities(ctx context.Context, code string) (*ReportAbilitiesQuery, error) {
+	raq := &ReportAbilitiesQuery{}
+	vars := map[string]any{
+		"code": graphql.String(code),
+	}
+	if err := w.Query(ctx, raq, vars); err != nil {
+		w.l.Error("ReportAbilitiesQuery failed", "error", err)
+		return nil, err
+	}
+
+	if raq.ReportData.Report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	return raq, nil
+}
+
+// GetGuildReports gets a page of the reports uploaded for a guild, newest first.
+func (w *WarcraftLogsClient) GetGuildReports(ctx context.Context, options *GuildReportsOptions) (*GuildReportsQuery, error) {
+	grq := &GuildReportsQuery{}
+	if err := w.Query(ctx, grq, options.vars()); err != nil {
+		w.l.Error("GuildReportsQuery failed", "error", err)
+		return nil, err
+	}
+
+	return grq, nil
+}
+
+// GetReportEvents gets every event of the given data type between the start & end time of the fights, following the
+// pages of events until there are none left. Each page is a query, so there are at most maxEventPages of them before
+// giving up with ErrTooManyEvents.
+func (w *WarcraftLogsClient) GetReportEvents(ctx context.Context, options *ReportEventsOptions) ([]*ReportEvent, error) {
+	fightIDs := make([]graphql.Int, 0, len(options.FightIDs))
+	for _, id := range options.FightIDs {
+		fightIDs = append(fightIDs, graphql.Int(id))
+	}
+
+	var events []*ReportEvent
+	startTime := options.StartTime
+	for pages := 0; ; pages++ {
+		if pages == maxEventPages {
+			return nil, ErrTooManyEvents
+		}
+
+		req := &ReportEventsQuery{}
+		vars := map[string]any{
+			"code":       graphql.String(options.Code),
+			"data_type":  options.DataType,
+			"fight_ids":  fightIDs,
+			"start_time": graphql.Float(startTime),
+			"end_time":   graphql.Float(options.EndTime),
+			"limit":      graphql.Int(eventPageLimit),
+		}
+		if err := w.Query(ctx, req, vars); err != nil {
+			w.l.Error("ReportEventsQuery failed", "error", err)
+			return nil, err
+		}
+
+		if req.ReportData.Report == nil || req.ReportData.Report.Events == nil {
+			return nil, ErrReportNotFound
+		}
+
+		page := req.ReportData.Report.Events
+		events = append(events, page.Data...)
+
+		if page.NextPageTimestamp == nil {
+			return events, nil
+		}
+		startTime = float64(*page.NextPageTimestamp)
+	}
+}
+
+// GetReportEventWindows gets every event of the given data type within each window, batching up to
+// MaxEventWindowBatch windows per query. A window with more than a page of events follows the rest of its pages with
+// GetReportEvents. The events are in the order of the windows.
+func (w *WarcraftLogsClient) GetReportEventWindows(ctx context.Context, options *ReportEventWindowsOptions) ([][]*ReportEvent, error) {
+	var events [][]*ReportEvent
+	for start := 0; start < len(options.Windows); start += MaxEventWindowBatch {
+		windows := options.Windows[start:min(start+MaxEventWindowBatch, len(options.Windows))]
+		batch := NewEventWindowBatch(options.Code, options.DataType, windows)
+		query, vars, err := batch.Query()
+		if err != nil {
+			w.l.Error("BatchReportEventsQuery failed", "error", err)
+			return nil, err
+		}
+
+		// Batches of different sizes cost different points, estimate them apart.
+		name := fmt.Sprintf("%s/%d", QueryName(&BatchReportEventsQuery{}), len(windows))
+
+		beq := &BatchReportEventsQuery{}
+		err = w.spend(ctx, name, beq, func(ctx context.Context, client *graphql.Client) error {
+			data, err := client.ExecRaw(ctx, query, vars)
+			if err != nil {
+				return err
+			}
+
+			decoded, err := batch.Decode(data)
+			if err != nil {
+				return err
+			}
+			*beq = *decoded
+
+			return nil
+		})
+		if err != nil {
+			w.l.Error("BatchReportEventsQuery failed", "error", err)
+			return nil, err
+		}
+
+		for i, page := range beq.Windows {
+			windowEvents := page.Data
+			if page.NextPageTimestamp != nil {
+				rest, err := w.GetReportEvents(ctx, &ReportEventsOptions{
+					Code:      options.Code,
+					DataType:  options.DataType,
+					FightIDs:  []int{windows[i].FightID},
+					StartTime: float64(*page.NextPageTimestamp),
+					EndTime:   windows[i].EndTime,
+				})
+				if err != nil {
+					return nil, err
+				}
+				windowEvents = append(windowEvents, rest...)
+			}
+
+			events = append(events, windowEvents)
+		}
+	}
+
+	return events, nil
+}
+
 // Query performs a query.
 func (w *WarcraftLogsClient) Query(ctx context.Context, query RatedQuery, vars map[string]interface{}) error {
+	return w.spend(ctx, QueryName(query), query, func(ctx context.Context, client *graphql.Client) error {
+		return client.Query(ctx, query, vars)
+	})
+}
+
+// spend runs the request against the point budget, estimating its cost by name beforehand and recording the points
+// spent from the query's RateLimitData afterwards.
+func (w *WarcraftLogsClient) spend(ctx context.Context, name string, query RatedQuery, request func(ctx context.Context, client *graphql.Client) error) error {
 	var cancel context.CancelFunc
 	if ctx == nil {
 		ctx, cancel = context.WithTimeout(context.Background(), time.Second*10)
 		defer cancel()
 	}
 
-	if err := w.limiter.CanSpendPoints(); err != nil {
+	// Only interactive queries may spend the points reserved for them.
+	share := 1.0
+	if limiter.PriorityFromContext(ctx) != limiter.Interactive {
+		share = 1 - limiter.DefaultInteractiveReserve
+	}
+
+	cost := w.costs.Estimate(name)
+
+	var err error
+	if w.waitForPoints {
+		err = WaitForPoints(ctx, w.limiter, cost, share)
+	} else {
+		err = w.limiter.CanSpend(cost, share)
+	}
+	if err != nil {
 		return err
 	}
 
+	// The points spent can only be attributed to this query when nothing else ran alongside it.
+	alone := w.inFlight.Add(1) == 1
+	observe := w.observesCosts()
+	before := w.limiter.Status().Spent
+	defer w.inFlight.Add(-1)
+
 	client := graphql.NewClient(WL_API_URL, w.config.Client(ctx))
-	if err := client.Query(ctx, query, vars); err != nil {
+	if err := request(ctx, client); err != nil {
 		var ne graphql.NetworkError
 		if errors.As(err, &ne) && ne.StatusCode() == http.StatusTooManyRequests {
 			w.limiter.SpendAllPoints()
@@ -116,39 +518,118 @@ func (w *WarcraftLogsClient) Query(ctx context.Context, query RatedQuery, vars m
 	}
 
 	w.limiter.SetPointsSpent(query.Data())
+	w.seeded.Store(true)
+
+	if observe && alone && w.inFlight.Load() == 1 {
+		w.costs.Observe(name, float64(query.Data().PointsSpentThisHour)-before)
+	}
 
 	return nil
 }
 
-// GetDefaultPartitionByZoneID returns the default partition for the give zoneID
-func (w *WarcraftLogsClient) GetDefaultPartitionByZoneID(zoneID int) int {
-	w.mu.Lock()
-	defer w.mu.Unlock()
+// observesCosts reports whether the points a query spends can be learnt from the budget. They can't until the budget
+// has been seeded by WarcraftLogs, nor when it's shared, as the other instances spend from it alongside us.
+func (w *WarcraftLogsClient) observesCosts() bool {
+	if _, shared := w.limiter.(*RedisPointLimiter); shared {
+		return false
+	}
 
-	for _, zone := range w.expansion.Zones {
-		if zone.ID == zoneID {
+	return w.seeded.Load()
+}
 
-			for _, partition := range zone.Partitions {
-				if partition.Default {
-					return partition.ID
-				}
-			}
+// GetPointBudget returns the points budget for this hour, how fast it's being spent and the estimated query costs.
+func (w *WarcraftLogsClient) GetPointBudget() *PointBudgetDTO {
+	status := w.limiter.Status()
+
+	dto := &PointBudgetDTO{
+		Limit:          status.Limit,
+		Spent:          status.Spent,
+		Remaining:      max(float64(status.Limit)-status.Spent, 0),
+		ResetInSeconds: int(math.Ceil(status.ResetIn.Seconds())),
+		EstimatedCosts: w.costs.Costs(),
+	}
+
+	// Points reset hourly, so whatever has elapsed of the hour is what they were spent over.
+	elapsed := time.Hour - status.ResetIn
+	if elapsed > 0 {
+		dto.SpendRatePerMinute = status.Spent / elapsed.Minutes()
+	}
+
+	if dto.SpendRatePerMinute > 0 && dto.Remaining > 0 {
+		exhaustIn := time.Duration(dto.Remaining / dto.SpendRatePerMinute * float64(time.Minute))
+		if exhaustIn < status.ResetIn {
+			at := time.Now().Add(exhaustIn)
+			dto.ProjectedExhaustion = &at
 		}
 	}
 
-	return -1
+	return dto
 }
 
-// ClearPartitionedExpansion sets the expansions to nil, allowing for it to be re-cached.
-func (w *WarcraftLogsClient) ClearPartitionedExpansion() {
-	w.mu.Lock()
-	defer w.mu.Unlock()
+// GetDefaultPartitionByZoneID returns the default partition for the given zoneID from any expansion, or nil when the
+// zone isn't partitioned. Unknown zones are an ErrZoneNotFound.
+func (w *WarcraftLogsClient) GetDefaultPartitionByZoneID(ctx context.Context, zoneID int) (*int, error) {
+	catalog, err := w.GetExpansionCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	w.expansion = nil
+	return catalog.DefaultPartition(zoneID)
 }
 
-// NewWarcraftLogsClient creates a new client and gets the remaining rate-limit.
+// ClearExpansionCatalog clears the expansion catalog, allowing for it to be re-cached.
+func (w *WarcraftLogsClient) ClearExpansionCatalog() {
+	w.catalog.Store(nil)
+}
+
+// Ready reports whether the client has finished its setup and can be queried.
+func (w *WarcraftLogsClient) Ready() bool {
+	return w.ready.Load()
+}
+
+// setup gets the remaining rate-limit and the expansions, retrying with backoff until both succeed.
+func (w *WarcraftLogsClient) setup() {
+	if w.config.ClientID == "" || w.config.ClientSecret == "" {
+		w.l.Error("WL_CLIENT_ID or WL_CLIENT_SECRET is missing, WarcraftLogs will remain unavailable")
+		return
+	}
+
+	backoff := initialSetupBackoff
+	for {
+		err := w.trySetup()
+		if err == nil {
+			break
+		}
+
+		w.l.Error("WarcraftLogs setup failed", "retry_in", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxSetupBackoff)
+	}
+
+	w.ready.Store(true)
+	w.l.Info("WarcraftLogs client ready")
+}
+
+// trySetup makes a single attempt at getting the remaining rate-limit and the expansions.
+func (w *WarcraftLogsClient) trySetup() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := w.GetRateLimit(ctx); err != nil {
+		return err
+	}
+
+	if _, err := w.GetExpansionEncounters(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewWarcraftLogsClient creates a new client, getting the remaining rate-limit and expansions in the background.
 func NewWarcraftLogsClient(l hclog.Logger) *WarcraftLogsClient {
+	waitForPoints, _ := strconv.ParseBool(os.Getenv("WL_WAIT_FOR_POINTS"))
+
 	wlc := &WarcraftLogsClient{
 		l: l,
 		config: &clientcredentials.Config{
@@ -156,23 +637,16 @@ func NewWarcraftLogsClient(l hclog.Logger) *WarcraftLogsClient {
 			ClientSecret: os.Getenv("WL_CLIENT_SECRET"),
 			TokenURL:     "https://www.warcraftlogs.com/oauth/token",
 		},
-		limiter: NewPointLimiter(l, RateLimitData{
+		limiter: NewPointBudget(l, RateLimitData{
 			LimitPerHour:        3600,
 			PointsSpentThisHour: 0,
 			PointsResetIn:       3600,
 		}),
-		expansion: nil,
+		costs:         NewCostEstimator(),
+		waitForPoints: waitForPoints,
 	}
 
-	if _, err := wlc.GetRateLimit(context.Background()); err != nil {
-		wlc.l.Error("Failed to get RateLimit during setup")
-		panic(err)
-	}
-
-	if _, err := wlc.GetExpansionEncounters(context.Background()); err != nil {
-		wlc.l.Error("Failed to get ExpansionEncounters during setup")
-		panic(err)
-	}
+	go wlc.setup()
 
 	return wlc
 }
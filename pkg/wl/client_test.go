@@ -0,0 +1,21 @@
+package wl
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWarcraftLogsClient_ObservesCosts(t *testing.T) {
+	w := &WarcraftLogsClient{limiter: NewPointLimiter(hclog.NewNullLogger(), RateLimitData{LimitPerHour: 3600, PointsResetIn: 3600})}
+
+	// The budget is our own guess until WarcraftLogs has seeded it.
+	assert.False(t, w.observesCosts())
+
+	w.seeded.Store(true)
+	assert.True(t, w.observesCosts())
+
+	// Other instances spend from a shared budget alongside us.
+	w.limiter = NewRedisPointLimiter(hclog.NewNullLogger(), nil)
+	assert.False(t, w.observesCosts())
+}
@@ -0,0 +1,76 @@
+package wl
+
+import (
+	"reflect"
+	"sync"
+)
+
+// costSmoothing is the weight given to the latest observed cost of a query.
+const costSmoothing = 0.3
+
+// defaultQueryCost is the cost estimated for a query we've yet to observe.
+const defaultQueryCost = 1.0
+
+// CostEstimator learns the points each type of query costs from the RateLimitData observed around it.
+type CostEstimator struct {
+	mu    sync.RWMutex
+	costs map[string]float64
+}
+
+// Estimate returns the expected cost of the named query.
+func (c *CostEstimator) Estimate(query string) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if cost, ok := c.costs[query]; ok {
+		return cost
+	}
+
+	return defaultQueryCost
+}
+
+// Observe records the points spent by a run of the named query.
+func (c *CostEstimator) Observe(query string, cost float64) {
+	// Spending nothing, or less than nothing, means the points reset mid-query.
+	if cost <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if prev, ok := c.costs[query]; ok {
+		cost = prev + costSmoothing*(cost-prev)
+	}
+	c.costs[query] = cost
+}
+
+// Costs returns the estimated cost of every query observed so far.
+func (c *CostEstimator) Costs() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	costs := make(map[string]float64, len(c.costs))
+	for k, v := range c.costs {
+		costs[k] = v
+	}
+
+	return costs
+}
+
+// NewCostEstimator creates a CostEstimator with no observations.
+func NewCostEstimator() *CostEstimator {
+	return &CostEstimator{
+		costs: map[string]float64{},
+	}
+}
+
+// QueryName returns the name costs are estimated by for the query, e.g. CharacterParsesQuery.
+func QueryName(query RatedQuery) string {
+	t := reflect.TypeOf(query)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,24 @@
+package wl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCostEstimator_Observe(t *testing.T) {
+	c := NewCostEstimator()
+	name := QueryName(&CharacterParsesQuery{})
+
+	assert.Equal(t, "CharacterParsesQuery", name)
+	assert.Equal(t, defaultQueryCost, c.Estimate(name))
+
+	c.Observe(name, 10)
+	assert.Equal(t, 10.0, c.Estimate(name))
+
+	c.Observe(name, 20)
+	assert.InDelta(t, 13.0, c.Estimate(name), 0.001)
+
+	// A reset mid-query shouldn't be learned from.
+	c.Observe(name, -50)
+	assert.InDelta(t, 13.0, c.Estimate(name), 0.001)
+}
@@ -1,8 +1,40 @@
 package wl
 
+import "time"
+
 type CharacterParseDTO struct {
-	Hidden       *bool           `json:"hidden,omitempty"`
-	ZoneRankings *ZoneRankingDTO `json:"zone_rankings,omitempty"`
+	Hidden       *bool            `json:"hidden,omitempty"`
+	Summary      *ParseSummaryDTO `json:"summary,omitempty"`
+	ZoneRankings *ZoneRankingDTO  `json:"zone_rankings,omitempty"`
+}
+
+// ParseSummaryDTO is derived from the ZoneRankingDTO, summarising the character's progress through the zone.
+type ParseSummaryDTO struct {
+	BestPerformanceAverage   *float64               `json:"best_performance_average,omitempty"`
+	MedianPerformanceAverage *float64               `json:"median_performance_average,omitempty"`
+	Bosses                   []*BossParseSummaryDTO `json:"bosses"`
+	KilledBosses             int                    `json:"killed_bosses"`
+	LockedInBosses           int                    `json:"locked_in_bosses"`
+	// LockedIn is whether every boss in the zone has been killed enough times for its parses to count.
+	LockedIn bool              `json:"locked_in"`
+	AllStars *AllStarTotalsDTO `json:"all_stars,omitempty"`
+}
+
+type BossParseSummaryDTO struct {
+	Encounter EncounterDTO `json:"encounter"`
+	Best      *float64     `json:"best,omitempty"`
+	Median    *float64     `json:"median,omitempty"`
+	Kills     int          `json:"kills"`
+	LockedIn  bool         `json:"locked_in"`
+	Spec      *string      `json:"spec,omitempty"`
+}
+
+type AllStarTotalsDTO struct {
+	Points         float64 `json:"points"`
+	PossiblePoints float64 `json:"possible_points"`
+	// Spec is the spec with the best all-star rank in the zone.
+	Spec string `json:"spec,omitempty"`
+	Rank int    `json:"rank,omitempty"`
 }
 
 type ZoneRankingDTO struct {
@@ -41,17 +73,191 @@ type RankingDTO struct {
 	BestAmount    float64      `json:"best_amount"`
 }
 
+type CharacterEncounterRankingDTO struct {
+	Hidden            *bool                `json:"hidden,omitempty"`
+	EncounterRankings *EncounterRankingDTO `json:"encounter_rankings,omitempty"`
+}
+
+type EncounterRankingDTO struct {
+	BestAmount         float64             `json:"best_amount"`
+	MedianPerformance  *float64            `json:"median_performance,omitempty"`
+	AveragePerformance *float64            `json:"average_performance,omitempty"`
+	TotalKills         int                 `json:"total_kills"`
+	FastestKill        *uint64             `json:"fastest_kill,omitempty"`
+	Difficulty         *int                `json:"difficulty,omitempty"`
+	Metric             *string             `json:"metric,omitempty"`
+	Partition          *int                `json:"partition,omitempty"`
+	Zone               *int                `json:"zone,omitempty"`
+	Ranks              []*EncounterRankDTO `json:"ranks"`
+}
+
+type EncounterRankDTO struct {
+	LockedIn          bool                    `json:"locked_in"`
+	RankPercent       *float64                `json:"rank_percent,omitempty"`
+	HistoricalPercent *float64                `json:"historical_percent,omitempty"`
+	TodayPercent      *float64                `json:"today_percent,omitempty"`
+	StartTime         time.Time               `json:"start_time"`
+	DurationMS        uint64                  `json:"duration_ms"`
+	Amount            float64                 `json:"amount"`
+	ItemLevel         *float64                `json:"item_level,omitempty"`
+	Spec              string                  `json:"spec"`
+	BestSpec          string                  `json:"best_spec"`
+	Guild             string                  `json:"guild,omitempty"`
+	Report            *EncounterRankReportDTO `json:"report,omitempty"`
+}
+
+type EncounterRankReportDTO struct {
+	Code    string `json:"code"`
+	FightID int    `json:"fight_id"`
+	URL     string `json:"url"`
+}
+
 type EncounterDTO struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+type ReportInfoDTO struct {
+	Code       string          `json:"code"`
+	Title      string          `json:"title"`
+	StartTime  time.Time       `json:"start_time"`
+	EndTime    time.Time       `json:"end_time"`
+	DurationMS uint64          `json:"duration_ms"`
+	Owner      *ReportOwnerDTO `json:"owner,omitempty"`
+	Zone       *ZoneDTO        `json:"zone,omitempty"`
+}
+
+type ReportDTO struct {
+	ReportInfoDTO
+	Encounters []*ReportEncounterDTO `json:"encounters"`
+	Fights     []*ReportFightDTO     `json:"fights"`
+	Players    []*ReportActorDTO     `json:"players"`
+}
+
+type ReportFightDetailDTO struct {
+	ReportInfoDTO
+	Fight *ReportFightDTO `json:"fight"`
+}
+
+type ReportOwnerDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ZoneDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ReportEncounterDTO struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	Pulls          int      `json:"pulls"`
+	Kills          int      `json:"kills"`
+	BestPercentage *float64 `json:"best_percentage,omitempty"`
+}
+
+type ReportFightDTO struct {
+	ID               int               `json:"id"`
+	EncounterID      int               `json:"encounter_id"`
+	Name             string            `json:"name"`
+	StartTime        uint64            `json:"start_time"`
+	EndTime          uint64            `json:"end_time"`
+	DurationMS       uint64            `json:"duration_ms"`
+	Kill             *bool             `json:"kill,omitempty"`
+	Difficulty       *int              `json:"difficulty,omitempty"`
+	Size             *int              `json:"size,omitempty"`
+	BossPercentage   *float64          `json:"boss_percentage,omitempty"`
+	FightPercentage  *float64          `json:"fight_percentage,omitempty"`
+	AverageItemLevel *float64          `json:"average_item_level,omitempty"`
+	Players          []*ReportActorDTO `json:"players,omitempty"`
+}
+
+type ReportActorDTO struct {
+	ID     int    `json:"id"`
+	GameID int    `json:"game_id"`
+	Name   string `json:"name"`
+	Server string `json:"server,omitempty"`
+	Class  string `json:"class"`
+}
+
+type ReportTableDTO struct {
+	DataType    ReportDataType   `json:"data_type"`
+	FightIDs    []int            `json:"fight_ids"`
+	TotalTimeMS uint64           `json:"total_time_ms"`
+	Entries     []*TableEntryDTO `json:"entries"`
+}
+
+type TableEntryDTO struct {
+	ID           int                `json:"id"`
+	GUID         int64              `json:"guid"`
+	Name         string             `json:"name"`
+	Type         string             `json:"type"`
+	Icon         string             `json:"icon"`
+	ItemLevel    *float64           `json:"item_level,omitempty"`
+	Total        float64            `json:"total"`
+	PerSecond    *float64           `json:"per_second,omitempty"`
+	ActiveTimeMS *uint64            `json:"active_time_ms,omitempty"`
+	Timestamp    *uint64            `json:"timestamp,omitempty"`
+	Fight        *int               `json:"fight,omitempty"`
+	KillingBlow  *TableAbilityDTO   `json:"killing_blow,omitempty"`
+	Abilities    []*TableAbilityDTO `json:"abilities,omitempty"`
+}
+
+type TableAbilityDTO struct {
+	GUID  int64   `json:"guid"`
+	Name  string  `json:"name"`
+	Icon  string  `json:"icon"`
+	Total float64 `json:"total"`
+}
+
+type ReportGraphDTO struct {
+	DataType ReportDataType    `json:"data_type"`
+	FightIDs []int             `json:"fight_ids"`
+	Series   []*GraphSeriesDTO `json:"series"`
+}
+
+type GraphSeriesDTO struct {
+	ID              int       `json:"id"`
+	GUID            int64     `json:"guid"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	PointStartMS    uint64    `json:"point_start_ms"`
+	PointIntervalMS uint64    `json:"point_interval_ms"`
+	Total           float64   `json:"total"`
+	Points          []float64 `json:"points"`
+}
+
 type PartitionedExpansion struct {
 	ID    int             `json:"id"`
 	Name  string          `json:"name"`
 	Zones []ExpansionZone `json:"zones"`
 }
 
+// DTO summarises the expansion without the encounters & partitions of its zones.
+func (e *PartitionedExpansion) DTO() *ExpansionDTO {
+	dto := &ExpansionDTO{
+		ID:    e.ID,
+		Name:  e.Name,
+		Zones: []ZoneDTO{},
+	}
+
+	for _, z := range e.Zones {
+		dto.Zones = append(dto.Zones, ZoneDTO{
+			ID:   z.ID,
+			Name: z.Name,
+		})
+	}
+
+	return dto
+}
+
+type ExpansionDTO struct {
+	ID    int       `json:"id"`
+	Name  string    `json:"name"`
+	Zones []ZoneDTO `json:"zones"`
+}
+
 func PartitionedExpansionFromExpansionEncounterQuery(q *ExpansionEncountersQuery) []*PartitionedExpansion {
 	var result []*PartitionedExpansion
 
@@ -68,6 +274,7 @@ func PartitionedExpansionFromExpansionEncounterQuery(q *ExpansionEncountersQuery
 			peZone := ExpansionZone{
 				ID:         int(zone.ID),
 				Name:       string(zone.Name),
+				Frozen:     bool(zone.Frozen),
 				Encounters: zone.GetZoneEncounters(),
 				Partitions: zone.GetZonePartitions(),
 			}
@@ -81,8 +288,10 @@ func PartitionedExpansionFromExpansionEncounterQuery(q *ExpansionEncountersQuery
 }
 
 type ExpansionZone struct {
-	ID         int             `json:"id"`
-	Name       string          `json:"name"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Frozen zones are no longer ranked, e.g. the raids of a previous season.
+	Frozen     bool            `json:"frozen"`
 	Encounters []ZoneEncounter `json:"encounters"`
 	Partitions []ZonePartition `json:"partitions"`
 }
@@ -98,3 +307,80 @@ type ZonePartition struct {
 	CompactName string `json:"compact_name"`
 	Default     bool   `json:"default"`
 }
+
+type PointBudgetDTO struct {
+	Limit               int                `json:"limit"`
+	Spent               float64            `json:"spent"`
+	Remaining           float64            `json:"remaining"`
+	ResetInSeconds      int                `json:"reset_in_seconds"`
+	SpendRatePerMinute  float64            `json:"spend_rate_per_minute"`
+	ProjectedExhaustion *time.Time         `json:"projected_exhaustion,omitempty"`
+	EstimatedCosts      map[string]float64 `json:"estimated_costs"`
+}
+
+type GuildReportsDTO struct {
+	Reports      []ReportInfoDTO `json:"reports"`
+	Total        int             `json:"total"`
+	PerPage      int             `json:"per_page"`
+	CurrentPage  int             `json:"current_page"`
+	LastPage     int             `json:"last_page"`
+	HasMorePages bool            `json:"has_more_pages"`
+}
+
+type EncounterCharacterRankingsDTO struct {
+	Encounter    EncounterDTO             `json:"encounter"`
+	Page         int                      `json:"page"`
+	HasMorePages bool                     `json:"has_more_pages"`
+	Count        int                      `json:"count"`
+	Rankings     []*WorldCharacterRankDTO `json:"rankings"`
+}
+
+type WorldCharacterRankDTO struct {
+	Rank       int                     `json:"rank"`
+	Name       string                  `json:"name"`
+	Class      string                  `json:"class"`
+	Spec       string                  `json:"spec"`
+	Amount     float64                 `json:"amount"`
+	DurationMS uint64                  `json:"duration_ms"`
+	StartTime  time.Time               `json:"start_time"`
+	ItemLevel  *float64                `json:"item_level,omitempty"`
+	Guild      string                  `json:"guild,omitempty"`
+	Server     string                  `json:"server,omitempty"`
+	Region     string                  `json:"region,omitempty"`
+	Report     *EncounterRankReportDTO `json:"report,omitempty"`
+}
+
+type EncounterFightRankingsDTO struct {
+	Encounter    EncounterDTO         `json:"encounter"`
+	Page         int                  `json:"page"`
+	HasMorePages bool                 `json:"has_more_pages"`
+	Count        int                  `json:"count"`
+	Rankings     []*WorldFightRankDTO `json:"rankings"`
+}
+
+type WorldFightRankDTO struct {
+	Rank       int                     `json:"rank"`
+	DurationMS uint64                  `json:"duration_ms"`
+	StartTime  time.Time               `json:"start_time"`
+	Deaths     *int                    `json:"deaths,omitempty"`
+	Tanks      *int                    `json:"tanks,omitempty"`
+	Healers    *int                    `json:"healers,omitempty"`
+	Melee      *int                    `json:"melee,omitempty"`
+	Ranged     *int                    `json:"ranged,omitempty"`
+	ItemLevel  *float64                `json:"item_level,omitempty"`
+	Guild      string                  `json:"guild,omitempty"`
+	Server     string                  `json:"server,omitempty"`
+	Region     string                  `json:"region,omitempty"`
+	Report     *EncounterRankReportDTO `json:"report,omitempty"`
+}
+
+type BulkCharacterParsesDTO struct {
+	Characters []*CharacterParseSummaryDTO `json:"characters"`
+}
+
+type CharacterParseSummaryDTO struct {
+	BatchCharacter
+	Found   bool             `json:"found"`
+	Hidden  *bool            `json:"hidden,omitempty"`
+	Summary *ParseSummaryDTO `json:"summary,omitempty"`
+}
@@ -1,6 +1,18 @@
 package wl
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrReportNotFound    = errors.New("the report could not be found")
+	ErrFightNotFound     = errors.New("the fight could not be found in the report")
+	ErrEncounterNotFound = errors.New("the encounter could not be found")
+	ErrExpansionNotFound = errors.New("the expansion could not be found")
+	ErrZoneNotFound      = errors.New("the zone could not be found")
+	ErrTooManyEvents     = errors.New("the report has too many events to page through")
+)
 
 type ErrNoPointsLeft struct {
 	StatusCode       int
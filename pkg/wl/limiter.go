@@ -1,20 +1,83 @@
 package wl
 
 import (
+	"context"
 	"errors"
 	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/limiter"
+	"github.com/redis/go-redis/v9"
 	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// PointBudget tracks the points the client may spend against the WarcraftLogs API this hour.
+type PointBudget interface {
+	// CanSpendPoints determines if there are points to spend this hour.
+	CanSpendPoints() error
+	// CanSpend determines if cost points can be spent within the given share of this hour's limit.
+	CanSpend(cost, share float64) error
+	// SetPointsSpent updates the budget from the RateLimitData returned by a query.
+	SetPointsSpent(data RateLimitData)
+	// SpendAllPoints marks the budget as exhausted until it resets.
+	SpendAllPoints()
+	// Status returns a snapshot of the budget.
+	Status() PointStatus
+}
+
+// PointStatus is a snapshot of a PointBudget.
+type PointStatus struct {
+	Limit   int
+	Spent   float64
+	ResetIn time.Duration
+}
+
+// WaitForPoints blocks until cost points can be spent within the share of the budget's limit, waiting for the points
+// to reset when there aren't. It gives up early if the reset is past the context's deadline.
+func WaitForPoints(ctx context.Context, budget PointBudget, cost, share float64) error {
+	for {
+		err := budget.CanSpend(cost, share)
+
+		var errNoPointsLeft *ErrNoPointsLeft
+		if !errors.As(err, &errNoPointsLeft) {
+			return err
+		}
+
+		wait := time.Duration(max(errNoPointsLeft.RemainingSeconds, 1)) * time.Second
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// withinLimit determines if cost points can be spent on top of those spent without going over the limit. A query may
+// spend the last of the points, but there must be at least one left to spend for a query of unknown cost.
+func withinLimit(spent, cost, limit float64) bool {
+	if cost > 0 {
+		return math.Ceil(spent+cost) <= limit
+	}
+
+	return math.Ceil(spent) < limit
+}
+
+// PointLimiter is a PointBudget local to the running process.
 type PointLimiter struct {
 	l hclog.Logger
 
 	limitPerHour        int
 	pointsSpentThisHour float64
 	resetInSeconds      int
+	resetAt             time.Time
 
 	timer *time.Timer
 	mu    *sync.RWMutex
@@ -35,25 +98,49 @@ func (p *PointLimiter) SetPointsSpent(data RateLimitData) {
 	p.limitPerHour = int(data.LimitPerHour)
 	p.pointsSpentThisHour = float64(data.PointsSpentThisHour)
 
+	// Follow WarcraftLogs' reset rather than our own hourly guess.
+	if data.PointsResetIn > 0 {
+		p.resetInSeconds = int(data.PointsResetIn)
+		p.resetAt = time.Now().Add(time.Duration(p.resetInSeconds) * time.Second)
+		p.timer.Reset(time.Duration(p.resetInSeconds) * time.Second)
+	}
+
 	p.l.Info("PointLimiter", "limit", p.limitPerHour, "spent", p.pointsSpentThisHour)
 }
 
 // CanSpendPoints determines if there are points to spend this hour.
 func (p *PointLimiter) CanSpendPoints() error {
+	return p.CanSpend(0, 1)
+}
+
+// CanSpend determines if cost points can be spent within the given share of this hour's limit.
+func (p *PointLimiter) CanSpend(cost, share float64) error {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	if math.Ceil(p.pointsSpentThisHour) < float64(p.limitPerHour) {
+	if withinLimit(p.pointsSpentThisHour, cost, float64(p.limitPerHour)*share) {
 		return nil
 	}
 
 	return &ErrNoPointsLeft{
 		StatusCode:       http.StatusServiceUnavailable,
-		RemainingSeconds: p.resetInSeconds,
+		RemainingSeconds: int(math.Ceil(time.Until(p.resetAt).Seconds())),
 		Err:              errors.New("no points available to spend"),
 	}
 }
 
+// Status returns a snapshot of the points spent this hour.
+func (p *PointLimiter) Status() PointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return PointStatus{
+		Limit:   p.limitPerHour,
+		Spent:   p.pointsSpentThisHour,
+		ResetIn: time.Until(p.resetAt),
+	}
+}
+
 // tick is the func called by time.AfterFunc when the timer ticks.
 func (p *PointLimiter) tick() {
 	p.mu.Lock()
@@ -61,6 +148,7 @@ func (p *PointLimiter) tick() {
 
 	p.pointsSpentThisHour = 0
 	p.resetInSeconds = 3600
+	p.resetAt = time.Now().Add(time.Hour)
 
 	p.timer.Stop()
 	p.timer.Reset(time.Hour)
@@ -75,6 +163,7 @@ func NewPointLimiter(l hclog.Logger, data RateLimitData) *PointLimiter {
 		limitPerHour:        int(data.LimitPerHour),
 		pointsSpentThisHour: float64(data.PointsSpentThisHour),
 		resetInSeconds:      int(data.PointsResetIn),
+		resetAt:             time.Now().Add(time.Duration(data.PointsResetIn) * time.Second),
 		mu:                  &sync.RWMutex{},
 	}
 
@@ -82,3 +171,115 @@ func NewPointLimiter(l hclog.Logger, data RateLimitData) *PointLimiter {
 
 	return pl
 }
+
+const redisPointsKey = "limiter:wl:points"
+
+// RedisPointLimiter is a PointBudget kept in redis, sharing the points spent with every instance.
+type RedisPointLimiter struct {
+	l hclog.Logger
+
+	client *redis.Client
+}
+
+// SpendAllPoints marks the shared budget as exhausted until it resets.
+func (p *RedisPointLimiter) SpendAllPoints() {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+
+	limit, err := p.client.HGet(ctx, redisPointsKey, "limit").Result()
+	if err != nil {
+		p.l.Error("RedisPointLimiter failed to read limit", "error", err)
+		return
+	}
+
+	if err := p.client.HSet(ctx, redisPointsKey, "spent", limit).Err(); err != nil {
+		p.l.Error("RedisPointLimiter failed to spend all points", "error", err)
+	}
+}
+
+// SetPointsSpent updates the shared points spent, expiring them when the points reset.
+func (p *RedisPointLimiter) SetPointsSpent(data RateLimitData) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+
+	pipe := p.client.TxPipeline()
+	pipe.HSet(ctx, redisPointsKey, "limit", int(data.LimitPerHour), "spent", float64(data.PointsSpentThisHour))
+	pipe.Expire(ctx, redisPointsKey, time.Duration(data.PointsResetIn)*time.Second)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		p.l.Error("RedisPointLimiter failed to set points spent", "error", err)
+		return
+	}
+
+	p.l.Info("RedisPointLimiter", "limit", data.LimitPerHour, "spent", data.PointsSpentThisHour)
+}
+
+// CanSpendPoints determines if there are shared points to spend this hour.
+func (p *RedisPointLimiter) CanSpendPoints() error {
+	return p.CanSpend(0, 1)
+}
+
+// CanSpend determines if cost shared points can be spent within the given share of this hour's limit.
+func (p *RedisPointLimiter) CanSpend(cost, share float64) error {
+	status := p.Status()
+
+	// Without a shared budget we let the query through, WarcraftLogs will 429 us if we're out.
+	if status.Limit == 0 {
+		return nil
+	}
+
+	if withinLimit(status.Spent, cost, float64(status.Limit)*share) {
+		return nil
+	}
+
+	return &ErrNoPointsLeft{
+		StatusCode:       http.StatusServiceUnavailable,
+		RemainingSeconds: int(math.Ceil(status.ResetIn.Seconds())),
+		Err:              errors.New("no points available to spend"),
+	}
+}
+
+// Status returns a snapshot of the shared points spent this hour.
+func (p *RedisPointLimiter) Status() PointStatus {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+
+	pipe := p.client.Pipeline()
+	hmget := pipe.HMGet(ctx, redisPointsKey, "limit", "spent")
+	ttl := pipe.TTL(ctx, redisPointsKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		p.l.Error("RedisPointLimiter failed to read points", "error", err)
+		return PointStatus{}
+	}
+
+	vals := hmget.Val()
+	limitStr, _ := vals[0].(string)
+	spentStr, _ := vals[1].(string)
+	limit, _ := strconv.Atoi(limitStr)
+	spent, _ := strconv.ParseFloat(spentStr, 64)
+
+	return PointStatus{
+		Limit:   limit,
+		Spent:   spent,
+		ResetIn: max(ttl.Val(), 0),
+	}
+}
+
+// NewRedisPointLimiter creates a RedisPointLimiter using the given client.
+func NewRedisPointLimiter(l hclog.Logger, client *redis.Client) *RedisPointLimiter {
+	return &RedisPointLimiter{
+		l:      l,
+		client: client,
+	}
+}
+
+// NewPointBudget creates a RedisPointLimiter when the rate-limits are limiter.Shared, a PointLimiter otherwise.
+func NewPointBudget(l hclog.Logger, data RateLimitData) PointBudget {
+	if !limiter.Shared() {
+		return NewPointLimiter(l, data)
+	}
+
+	l.Info("Using shared rate-limit", "key", redisPointsKey)
+	return NewRedisPointLimiter(l, limiter.SharedClient(l))
+}
@@ -1,9 +1,11 @@
 package wl
 
 import (
+	"context"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestNewPointLimiter(t *testing.T) {
@@ -33,3 +35,34 @@ func TestNewPointLimiter(t *testing.T) {
 	assert.Equal(t, float64(0), pl.pointsSpentThisHour)
 	assert.Equal(t, 3600, pl.resetInSeconds)
 }
+
+func TestPointLimiter_CanSpend(t *testing.T) {
+	pl := NewPointLimiter(hclog.Default(), RateLimitData{
+		LimitPerHour:        10,
+		PointsSpentThisHour: 7,
+		PointsResetIn:       3600,
+	})
+
+	assert.Nil(t, pl.CanSpend(2, 1))
+	// The last of the points may be spent.
+	assert.Nil(t, pl.CanSpend(3, 1))
+	assert.NotNil(t, pl.CanSpend(3.5, 1))
+	assert.NotNil(t, pl.CanSpend(0, 0.5))
+}
+
+func TestWaitForPoints(t *testing.T) {
+	pl := NewPointLimiter(hclog.Default(), RateLimitData{
+		LimitPerHour:        5,
+		PointsSpentThisHour: 5,
+		PointsResetIn:       1,
+	})
+
+	// The reset is past the deadline, so we shouldn't wait at all.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.NotNil(t, WaitForPoints(ctx, pl, 1, 1))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	assert.Nil(t, WaitForPoints(ctx, pl, 1, 1))
+}
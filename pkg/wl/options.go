@@ -2,7 +2,9 @@ package wl
 
 import (
 	"context"
+	"github.com/hasura/go-graphql-client"
 	"github.com/heckin-dev/amashan/pkg/middleware"
+	"time"
 )
 
 type CharacterParsesQueryOptions struct {
@@ -11,6 +13,13 @@ type CharacterParsesQueryOptions struct {
 	ServerRegion string
 	ZoneID       int
 	Partition    *int
+	Metric       *CharacterRankingMetricType
+	Difficulty   *int
+	Size         *int
+	Role         *RoleType
+	ClassName    *string
+	SpecName     *string
+	Timeframe    *RankingTimeframeType
 }
 
 // CharacterParsesQueryOptionsFromContext creates a CharacterParsesQueryOptions from a given context.
@@ -24,3 +33,377 @@ func CharacterParsesQueryOptionsFromContext(ctx context.Context) *CharacterParse
 		Name:         ctx.Value(middleware.CharacterContextKey).(string),
 	}
 }
+
+// rankingVars builds the variables filtering the character's rankings, shared by the zone & encounter rankings.
+func (o *CharacterParsesQueryOptions) rankingVars() map[string]any {
+	var difficulty, size *graphql.Int
+	if o.Difficulty != nil {
+		d := graphql.Int(*o.Difficulty)
+		difficulty = &d
+	}
+	if o.Size != nil {
+		sz := graphql.Int(*o.Size)
+		size = &sz
+	}
+
+	var className, specName *graphql.String
+	if o.ClassName != nil {
+		c := graphql.String(*o.ClassName)
+		className = &c
+	}
+	if o.SpecName != nil {
+		sn := graphql.String(*o.SpecName)
+		specName = &sn
+	}
+
+	return map[string]any{
+		"name":          graphql.String(o.Name),
+		"server_slug":   graphql.String(o.ServerSlug),
+		"server_region": graphql.String(o.ServerRegion),
+		"metric":        o.Metric,
+		"difficulty":    difficulty,
+		"size":          size,
+		"role":          o.Role,
+		"class_name":    className,
+		"spec_name":     specName,
+		"timeframe":     o.Timeframe,
+	}
+}
+
+// CharacterRankingMetricType is the GraphQL enum for the metric a character is ranked by.
+type CharacterRankingMetricType string
+
+const (
+	MetricDefault     CharacterRankingMetricType = "default"
+	MetricDPS         CharacterRankingMetricType = "dps"
+	MetricRDPS        CharacterRankingMetricType = "rdps"
+	MetricADPS        CharacterRankingMetricType = "adps"
+	MetricNDPS        CharacterRankingMetricType = "ndps"
+	MetricCDPS        CharacterRankingMetricType = "cdps"
+	MetricWDPS        CharacterRankingMetricType = "wdps"
+	MetricBossDPS     CharacterRankingMetricType = "bossdps"
+	MetricBossRDPS    CharacterRankingMetricType = "bossrdps"
+	MetricBossADPS    CharacterRankingMetricType = "bossadps"
+	MetricBossNDPS    CharacterRankingMetricType = "bossndps"
+	MetricBossCDPS    CharacterRankingMetricType = "bosscdps"
+	MetricHPS         CharacterRankingMetricType = "hps"
+	MetricTankHPS     CharacterRankingMetricType = "tankhps"
+	MetricKRSI        CharacterRankingMetricType = "krsi"
+	MetricPlayerScore CharacterRankingMetricType = "playerscore"
+	MetricPlayerSpeed CharacterRankingMetricType = "playerspeed"
+)
+
+// RankingMetricsMap maps the query param form of each supported CharacterRankingMetricType to it.
+var RankingMetricsMap = map[string]CharacterRankingMetricType{
+	"default":     MetricDefault,
+	"dps":         MetricDPS,
+	"rdps":        MetricRDPS,
+	"adps":        MetricADPS,
+	"ndps":        MetricNDPS,
+	"cdps":        MetricCDPS,
+	"wdps":        MetricWDPS,
+	"bossdps":     MetricBossDPS,
+	"bossrdps":    MetricBossRDPS,
+	"bossadps":    MetricBossADPS,
+	"bossndps":    MetricBossNDPS,
+	"bosscdps":    MetricBossCDPS,
+	"hps":         MetricHPS,
+	"tankhps":     MetricTankHPS,
+	"krsi":        MetricKRSI,
+	"playerscore": MetricPlayerScore,
+	"playerspeed": MetricPlayerSpeed,
+}
+
+// RoleType is the GraphQL enum for the role a character is ranked as.
+type RoleType string
+
+const (
+	RoleAny    RoleType = "Any"
+	RoleDPS    RoleType = "DPS"
+	RoleHealer RoleType = "Healer"
+	RoleTank   RoleType = "Tank"
+)
+
+// RolesMap maps the query param form of each RoleType to it.
+var RolesMap = map[string]RoleType{
+	"any":    RoleAny,
+	"dps":    RoleDPS,
+	"healer": RoleHealer,
+	"tank":   RoleTank,
+}
+
+// RankingTimeframeType is the GraphQL enum for whether rankings are compared with today's or historical parses.
+type RankingTimeframeType string
+
+const (
+	TimeframeToday      RankingTimeframeType = "Today"
+	TimeframeHistorical RankingTimeframeType = "Historical"
+)
+
+// TimeframesMap maps the query param form of each RankingTimeframeType to it.
+var TimeframesMap = map[string]RankingTimeframeType{
+	"today":      TimeframeToday,
+	"historical": TimeframeHistorical,
+}
+
+// DifficultiesMap maps the query param form of each raid difficulty to its WarcraftLogs ID.
+var DifficultiesMap = map[string]int{
+	"lfr":    1,
+	"normal": 3,
+	"heroic": 4,
+	"mythic": 5,
+}
+
+// ReportDataType is the type of data a report table or graph is built from.
+type ReportDataType string
+
+const (
+	DamageDone  ReportDataType = "DamageDone"
+	Healing     ReportDataType = "Healing"
+	DamageTaken ReportDataType = "DamageTaken"
+	Deaths      ReportDataType = "Deaths"
+	Casts       ReportDataType = "Casts"
+)
+
+// ReportDataTypesMap maps the route form of each supported ReportDataType to it.
+var ReportDataTypesMap = map[string]ReportDataType{
+	"damage-done":  DamageDone,
+	"healing":      Healing,
+	"damage-taken": DamageTaken,
+	"deaths":       Deaths,
+	"casts":        Casts,
+}
+
+// TableDataType is the GraphQL enum for the dataType of report.table.
+type TableDataType string
+
+// GraphDataType is the GraphQL enum for the dataType of report.graph.
+type GraphDataType string
+
+type ReportDataOptions struct {
+	Code      string
+	DataType  ReportDataType
+	FightIDs  []int
+	SourceID  *int
+	AbilityID *int
+}
+
+// vars builds the variables shared by the report table & graph queries.
+func (o *ReportDataOptions) vars() map[string]any {
+	fightIDs := make([]graphql.Int, 0, len(o.FightIDs))
+	for _, id := range o.FightIDs {
+		fightIDs = append(fightIDs, graphql.Int(id))
+	}
+
+	var sourceID *graphql.Int
+	if o.SourceID != nil {
+		id := graphql.Int(*o.SourceID)
+		sourceID = &id
+	}
+
+	var abilityID *graphql.Float
+	if o.AbilityID != nil {
+		id := graphql.Float(*o.AbilityID)
+		abilityID = &id
+	}
+
+	return map[string]any{
+		"code":       graphql.String(o.Code),
+		"fight_ids":  fightIDs,
+		"source_id":  sourceID,
+		"ability_id": abilityID,
+	}
+}
+
+// EventDataType is the GraphQL enum for the dataType of report.events.
+type EventDataType string
+
+const (
+	DeathEvents       EventDataType = "Deaths"
+	DamageTakenEvents EventDataType = "DamageTaken"
+)
+
+type ReportEventsOptions struct {
+	Code      string
+	DataType  EventDataType
+	FightIDs  []int
+	StartTime float64
+	EndTime   float64
+}
+
+// ReportEventWindow is the time window of a single fight the events are gotten between.
+type ReportEventWindow struct {
+	FightID   int
+	StartTime float64
+	EndTime   float64
+}
+
+type ReportEventWindowsOptions struct {
+	Code     string
+	DataType EventDataType
+	Windows  []ReportEventWindow
+}
+
+type GuildReportsOptions struct {
+	GuildName    string
+	ServerSlug   string
+	ServerRegion string
+	ZoneID       *int
+	StartTime    *time.Time
+	EndTime      *time.Time
+	Page         int
+	Limit        int
+}
+
+// GuildReportsOptionsFromContext creates a GuildReportsOptions for the first page from a given context.
+//
+// It is expected that the context contains the middleware.RegionContextKey, middleware.RealmContextKey &
+// middleware.GuildContextKey
+func GuildReportsOptionsFromContext(ctx context.Context) *GuildReportsOptions {
+	return &GuildReportsOptions{
+		ServerRegion: ctx.Value(middleware.RegionContextKey).(string),
+		ServerSlug:   ctx.Value(middleware.RealmContextKey).(string),
+		GuildName:    ctx.Value(middleware.GuildContextKey).(string),
+		Page:         1,
+		Limit:        DefaultGuildReportsLimit,
+	}
+}
+
+// DefaultGuildReportsLimit is the number of reports per page WarcraftLogs returns by default.
+const DefaultGuildReportsLimit = 100
+
+func (o *GuildReportsOptions) vars() map[string]any {
+	var zoneID *graphql.Int
+	if o.ZoneID != nil {
+		id := graphql.Int(*o.ZoneID)
+		zoneID = &id
+	}
+
+	var startTime, endTime *graphql.Float
+	if o.StartTime != nil {
+		t := graphql.Float(o.StartTime.UnixMilli())
+		startTime = &t
+	}
+	if o.EndTime != nil {
+		t := graphql.Float(o.EndTime.UnixMilli())
+		endTime = &t
+	}
+
+	return map[string]any{
+		"guild_name":          graphql.String(o.GuildName),
+		"guild_server_slug":   graphql.String(o.ServerSlug),
+		"guild_server_region": graphql.String(o.ServerRegion),
+		"zone_id":             zoneID,
+		"start_time":          startTime,
+		"end_time":            endTime,
+		"page":                graphql.Int(max(o.Page, 1)),
+		"limit":               graphql.Int(o.Limit),
+	}
+}
+
+// FightRankingMetricType is the GraphQL enum for the metric fights are ranked by.
+type FightRankingMetricType string
+
+const (
+	FightMetricDefault   FightRankingMetricType = "default"
+	FightMetricExecution FightRankingMetricType = "execution"
+	FightMetricFeats     FightRankingMetricType = "feats"
+	FightMetricScore     FightRankingMetricType = "score"
+	FightMetricSpeed     FightRankingMetricType = "speed"
+	FightMetricProgress  FightRankingMetricType = "progress"
+)
+
+// FightMetricsMap maps the query param form of each FightRankingMetricType to it.
+var FightMetricsMap = map[string]FightRankingMetricType{
+	"default":   FightMetricDefault,
+	"execution": FightMetricExecution,
+	"feats":     FightMetricFeats,
+	"score":     FightMetricScore,
+	"speed":     FightMetricSpeed,
+	"progress":  FightMetricProgress,
+}
+
+// WorldRankingsOptions filters the world rankings of an encounter. Metric applies to the character rankings,
+// FightMetric to the fight rankings, ClassName & SpecName only to the character rankings.
+type WorldRankingsOptions struct {
+	EncounterID  int
+	ServerRegion *string
+	ClassName    *string
+	SpecName     *string
+	Metric       *CharacterRankingMetricType
+	FightMetric  *FightRankingMetricType
+	Difficulty   *int
+	Size         *int
+	Partition    *int
+	Page         int
+}
+
+// vars builds the variables shared by the character & fight rankings of an encounter.
+func (o *WorldRankingsOptions) vars() map[string]any {
+	var difficulty, size, partition *graphql.Int
+	if o.Difficulty != nil {
+		d := graphql.Int(*o.Difficulty)
+		difficulty = &d
+	}
+	if o.Size != nil {
+		sz := graphql.Int(*o.Size)
+		size = &sz
+	}
+	if o.Partition != nil {
+		p := graphql.Int(*o.Partition)
+		partition = &p
+	}
+
+	var serverRegion *graphql.String
+	if o.ServerRegion != nil {
+		r := graphql.String(*o.ServerRegion)
+		serverRegion = &r
+	}
+
+	return map[string]any{
+		"encounter_id":  graphql.Int(o.EncounterID),
+		"server_region": serverRegion,
+		"difficulty":    difficulty,
+		"size":          size,
+		"partition":     partition,
+		"page":          graphql.Int(max(o.Page, 1)),
+	}
+}
+
+// characterVars builds the variables of the character rankings of an encounter.
+func (o *WorldRankingsOptions) characterVars() map[string]any {
+	var className, specName *graphql.String
+	if o.ClassName != nil {
+		c := graphql.String(*o.ClassName)
+		className = &c
+	}
+	if o.SpecName != nil {
+		sn := graphql.String(*o.SpecName)
+		specName = &sn
+	}
+
+	vars := o.vars()
+	vars["class_name"] = className
+	vars["spec_name"] = specName
+	vars["metric"] = o.Metric
+
+	return vars
+}
+
+// fightVars builds the variables of the fight rankings of an encounter.
+func (o *WorldRankingsOptions) fightVars() map[string]any {
+	vars := o.vars()
+	vars["metric"] = o.FightMetric
+
+	return vars
+}
+
+// nullableInt converts an optional int to the variable of a nullable Int.
+func nullableInt(v *int) *graphql.Int {
+	if v == nil {
+		return nil
+	}
+
+	i := graphql.Int(*v)
+	return &i
+}
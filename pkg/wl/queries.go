@@ -1,5 +1,11 @@
 package wl
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
 type RatedQuery interface {
 	Data() RateLimitData
 }
@@ -41,15 +47,385 @@ func (c *CharacterParsesQuery) Data() RateLimitData {
 
 func (c *CharacterParsesQuery) ToDTO() *CharacterParseDTO {
 	dto := &CharacterParseDTO{}
+	if c.CharacterData.Character == nil {
+		return dto
+	}
+
 	if c.CharacterData.Character.Hidden != nil {
 		dto.Hidden = Bool(bool(*c.CharacterData.Character.Hidden))
 	}
 
-	dto.ZoneRankings = c.CharacterData.Character.ZoneRankings.DTO()
+	if c.CharacterData.Character.ZoneRankings != nil {
+		dto.ZoneRankings = c.CharacterData.Character.ZoneRankings.DTO()
+		dto.Summary = summarizeZoneRankings(dto.ZoneRankings)
+	}
+
+	return dto
+}
+
+// summarizeZoneRankings derives the best & median parse per boss, the all-star totals and whether the character's
+// parses are locked in from their zone rankings.
+func summarizeZoneRankings(zr *ZoneRankingDTO) *ParseSummaryDTO {
+	summary := &ParseSummaryDTO{
+		BestPerformanceAverage:   zr.BestPerformanceAverage,
+		MedianPerformanceAverage: zr.MedianPerformanceAverage,
+		Bosses:                   []*BossParseSummaryDTO{},
+	}
+
+	var allStars *AllStarTotalsDTO
+	for _, r := range zr.Rankings {
+		summary.Bosses = append(summary.Bosses, &BossParseSummaryDTO{
+			Encounter: r.Encounter,
+			Best:      r.RankPercent,
+			Median:    r.MedianPercent,
+			Kills:     r.TotalKills,
+			LockedIn:  r.LockedIn,
+			Spec:      r.Spec,
+		})
+
+		if r.TotalKills > 0 {
+			summary.KilledBosses++
+		}
+		if r.LockedIn {
+			summary.LockedInBosses++
+		}
+
+		if r.AllStars != nil {
+			if allStars == nil {
+				allStars = &AllStarTotalsDTO{}
+			}
+			allStars.Points += r.AllStars.Points
+			allStars.PossiblePoints += r.AllStars.PossiblePoints
+		}
+	}
+
+	summary.LockedIn = len(zr.Rankings) > 0 && summary.LockedInBosses == len(zr.Rankings)
+
+	for _, as := range zr.AllStars {
+		if allStars == nil {
+			allStars = &AllStarTotalsDTO{}
+		}
+		if allStars.Rank == 0 || (as.Rank > 0 && as.Rank < allStars.Rank) {
+			allStars.Spec = as.Spec
+			allStars.Rank = as.Rank
+		}
+	}
+	summary.AllStars = allStars
+
+	return summary
+}
+
+// BatchCharacterParsesQuery is the response of a CharacterBatch, with a CharacterParsesQuery per character.
+type BatchCharacterParsesQuery struct {
+	Characters    []*CharacterParsesQuery
+	RateLimitData RateLimitData
+}
+
+func (b *BatchCharacterParsesQuery) Data() RateLimitData {
+	return b.RateLimitData
+}
+
+type EncounterRankingsQuery struct {
+	CharacterData CharacterEncounterData
+	RateLimitData RateLimitData
+}
+
+func (e *EncounterRankingsQuery) Data() RateLimitData {
+	return e.RateLimitData
+}
+
+func (e *EncounterRankingsQuery) ToDTO() *CharacterEncounterRankingDTO {
+	dto := &CharacterEncounterRankingDTO{}
+	if e.CharacterData.Character == nil {
+		return dto
+	}
+
+	if e.CharacterData.Character.Hidden != nil {
+		dto.Hidden = Bool(bool(*e.CharacterData.Character.Hidden))
+	}
+
+	if e.CharacterData.Character.EncounterRankings != nil {
+		dto.EncounterRankings = e.CharacterData.Character.EncounterRankings.DTO()
+	}
+
+	return dto
+}
+
+// worldRankingsPerPage is how many rankings WarcraftLogs returns per page of an encounter's world rankings.
+const worldRankingsPerPage = 100
+
+type WorldEncounterCharacterRankingsQuery struct {
+	WorldData     WorldEncounterCharacterRankingsData
+	RateLimitData RateLimitData
+}
+
+func (w *WorldEncounterCharacterRankingsQuery) Data() RateLimitData {
+	return w.RateLimitData
+}
+
+func (w *WorldEncounterCharacterRankingsQuery) ToDTO() *EncounterCharacterRankingsDTO {
+	dto := &EncounterCharacterRankingsDTO{
+		Rankings: []*WorldCharacterRankDTO{},
+	}
+
+	encounter := w.WorldData.Encounter
+	if encounter == nil {
+		return dto
+	}
+	dto.Encounter = encounter.Encounter.DTO()
+
+	page := encounter.CharacterRankings
+	if page == nil {
+		return dto
+	}
+
+	dto.Page = page.Page
+	dto.HasMorePages = page.HasMorePages
+	dto.Count = page.Count
+	for i, r := range page.Rankings {
+		dto.Rankings = append(dto.Rankings, r.DTO((max(page.Page, 1)-1)*worldRankingsPerPage+i+1))
+	}
+
+	return dto
+}
+
+type WorldEncounterFightRankingsQuery struct {
+	WorldData     WorldEncounterFightRankingsData
+	RateLimitData RateLimitData
+}
+
+func (w *WorldEncounterFightRankingsQuery) Data() RateLimitData {
+	return w.RateLimitData
+}
+
+func (w *WorldEncounterFightRankingsQuery) ToDTO() *EncounterFightRankingsDTO {
+	dto := &EncounterFightRankingsDTO{
+		Rankings: []*WorldFightRankDTO{},
+	}
+
+	encounter := w.WorldData.Encounter
+	if encounter == nil {
+		return dto
+	}
+	dto.Encounter = encounter.Encounter.DTO()
+
+	page := encounter.FightRankings
+	if page == nil {
+		return dto
+	}
+
+	dto.Page = page.Page
+	dto.HasMorePages = page.HasMorePages
+	dto.Count = page.Count
+	for i, r := range page.Rankings {
+		dto.Rankings = append(dto.Rankings, r.DTO((max(page.Page, 1)-1)*worldRankingsPerPage+i+1))
+	}
+
+	return dto
+}
+
+type ReportQuery struct {
+	ReportData    ReportData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+func (r *ReportQuery) ToDTO() *ReportDTO {
+	report := r.ReportData.Report
+	dto := &ReportDTO{
+		ReportInfoDTO: report.ReportInfo.DTO(),
+	}
+
+	for _, p := range report.Players() {
+		dto.Players = append(dto.Players, p)
+	}
+	slices.SortFunc(dto.Players, func(a, b *ReportActorDTO) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	encounters := map[int]*ReportEncounterDTO{}
+	for _, f := range report.Fights {
+		fight := f.DTO(nil)
+		dto.Fights = append(dto.Fights, fight)
+
+		// Trash fights have no encounter.
+		if fight.EncounterID == 0 {
+			continue
+		}
+
+		e, ok := encounters[fight.EncounterID]
+		if !ok {
+			e = &ReportEncounterDTO{
+				ID:   fight.EncounterID,
+				Name: fight.Name,
+			}
+			encounters[fight.EncounterID] = e
+			dto.Encounters = append(dto.Encounters, e)
+		}
+
+		e.Pulls++
+		if fight.Kill != nil && *fight.Kill {
+			e.Kills++
+		}
+
+		if fight.BossPercentage != nil && (e.BestPercentage == nil || *fight.BossPercentage < *e.BestPercentage) {
+			e.BestPercentage = Float(*fight.BossPercentage)
+		}
+	}
+
+	return dto
+}
+
+type ReportFightQuery struct {
+	ReportData    ReportFightData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportFightQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+func (r *ReportFightQuery) ToDTO() *ReportFightDetailDTO {
+	report := r.ReportData.Report
+	dto := &ReportFightDetailDTO{
+		ReportInfoDTO: report.ReportInfo.DTO(),
+	}
+
+	if len(report.Fights) > 0 {
+		dto.Fight = report.Fights[0].DTO(report.Players())
+	}
 
 	return dto
 }
 
+type ReportTableQuery struct {
+	ReportData    ReportTableData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportTableQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+func (r *ReportTableQuery) ToDTO() *ReportTableDTO {
+	if r.ReportData.Report == nil || r.ReportData.Report.Table == nil {
+		return &ReportTableDTO{}
+	}
+
+	return r.ReportData.Report.Table.DTO()
+}
+
+type ReportGraphQuery struct {
+	ReportData    ReportGraphData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportGraphQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+func (r *ReportGraphQuery) ToDTO() *ReportGraphDTO {
+	if r.ReportData.Report == nil || r.ReportData.Report.Graph == nil {
+		return &ReportGraphDTO{}
+	}
+
+	return r.ReportData.Report.Graph.DTO()
+}
+
+type ReportEventsQuery struct {
+	ReportData    ReportEventsData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportEventsQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+// BatchReportEventsQuery is the response of an EventWindowBatch, with the first page of events of each window.
+type BatchReportEventsQuery struct {
+	Windows       []*ReportEventPaginator
+	RateLimitData RateLimitData
+}
+
+func (b *BatchReportEventsQuery) Data() RateLimitData {
+	return b.RateLimitData
+}
+
+type ReportAbilitiesQuery struct {
+	ReportData    ReportAbilitiesData
+	RateLimitData RateLimitData
+}
+
+func (r *ReportAbilitiesQuery) Data() RateLimitData {
+	return r.RateLimitData
+}
+
+// Abilities returns the abilities used in the report by their game ID.
+func (r *ReportAbilitiesQuery) Abilities() map[int]*TableAbilityDTO {
+	abilities := map[int]*TableAbilityDTO{}
+	if r.ReportData.Report == nil || r.ReportData.Report.MasterData == nil {
+		return abilities
+	}
+
+	for _, a := range r.ReportData.Report.MasterData.Abilities {
+		abilities[int(a.GameID)] = &TableAbilityDTO{
+			GUID: int64(a.GameID),
+			Name: string(a.Name),
+			Icon: string(a.Icon),
+		}
+	}
+
+	return abilities
+}
+
+// ReportURL links to the fight of a report on WarcraftLogs.
+func ReportURL(code string, fightID int) string {
+	return fmt.Sprintf("%s/%s#fight=%d", WL_REPORT_URL, code, fightID)
+}
+
 func Bool(v bool) *bool {
 	return &v
 }
+
+func Int(v int) *int {
+	return &v
+}
+
+func Float(v float64) *float64 {
+	return &v
+}
+
+type GuildReportsQuery struct {
+	ReportData    ReportsData
+	RateLimitData RateLimitData
+}
+
+func (g *GuildReportsQuery) Data() RateLimitData {
+	return g.RateLimitData
+}
+
+func (g *GuildReportsQuery) ToDTO() *GuildReportsDTO {
+	dto := &GuildReportsDTO{
+		Reports: []ReportInfoDTO{},
+	}
+
+	page := g.ReportData.Reports
+	if page == nil {
+		return dto
+	}
+
+	for _, r := range page.Data {
+		dto.Reports = append(dto.Reports, r.DTO())
+	}
+
+	dto.Total = int(page.Total)
+	dto.PerPage = int(page.PerPage)
+	dto.CurrentPage = int(page.CurrentPage)
+	dto.LastPage = int(page.LastPage)
+	dto.HasMorePages = bool(page.HasMorePages)
+
+	return dto
+}
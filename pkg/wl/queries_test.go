@@ -0,0 +1,250 @@
+package wl
+
+import (
+	"encoding/json"
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func boolean(v bool) *graphql.Boolean {
+	b := graphql.Boolean(v)
+	return &b
+}
+
+func float(v float64) *graphql.Float {
+	f := graphql.Float(v)
+	return &f
+}
+
+func TestReportQuery_ToDTO(t *testing.T) {
+	rq := &ReportQuery{
+		ReportData: ReportData{
+			Report: &Report{
+				ReportInfo: ReportInfo{
+					Code:      "abcdEFGH1234ijkl",
+					Title:     "Raid Night",
+					StartTime: 1700000000000,
+					EndTime:   1700003600000,
+					MasterData: &ReportMasterData{
+						Actors: []ReportActor{
+							{ID: 2, Name: "Skxyz", SubType: "Mage"},
+							{ID: 1, Name: "Aradin", SubType: "Paladin"},
+						},
+					},
+				},
+				Fights: []ReportFight{
+					{ID: 1, EncounterID: 0, Name: "Trash", StartTime: 0, EndTime: 1000},
+					{ID: 2, EncounterID: 2902, Name: "Ulgrax", StartTime: 2000, EndTime: 5000, Kill: boolean(false), BossPercentage: float(40)},
+					{ID: 3, EncounterID: 2902, Name: "Ulgrax", StartTime: 6000, EndTime: 9000, Kill: boolean(false), BossPercentage: float(12.5)},
+					{ID: 4, EncounterID: 2902, Name: "Ulgrax", StartTime: 10000, EndTime: 14000, Kill: boolean(true), BossPercentage: float(0)},
+				},
+			},
+		},
+	}
+
+	dto := rq.ToDTO()
+
+	assert.Equal(t, "Raid Night", dto.Title)
+	assert.Equal(t, uint64(3600000), dto.DurationMS)
+	assert.Len(t, dto.Fights, 4)
+	assert.Equal(t, "Aradin", dto.Players[0].Name)
+
+	assert.Len(t, dto.Encounters, 1)
+	assert.Equal(t, 3, dto.Encounters[0].Pulls)
+	assert.Equal(t, 1, dto.Encounters[0].Kills)
+	assert.Equal(t, 0.0, *dto.Encounters[0].BestPercentage)
+}
+
+func TestReportFightQuery_ToDTO(t *testing.T) {
+	rfq := &ReportFightQuery{
+		ReportData: ReportFightData{
+			Report: &ReportWithFight{
+				ReportInfo: ReportInfo{
+					MasterData: &ReportMasterData{
+						Actors: []ReportActor{
+							{ID: 1, Name: "Aradin", SubType: "Paladin"},
+							{ID: 2, Name: "Skxyz", SubType: "Mage"},
+						},
+					},
+				},
+				Fights: []ReportFight{
+					{ID: 7, EncounterID: 2902, Name: "Ulgrax", FriendlyPlayers: []graphql.Int{2}},
+				},
+			},
+		},
+	}
+
+	dto := rfq.ToDTO()
+
+	assert.Equal(t, 7, dto.Fight.ID)
+	assert.Len(t, dto.Fight.Players, 1)
+	assert.Equal(t, "Skxyz", dto.Fight.Players[0].Name)
+}
+
+func TestReportTableScalar_DTO(t *testing.T) {
+	raw := `{"data": {"totalTime": 200000, "entries": [
+		{"name": "Skxyz", "id": 2, "guid": 242328372, "type": "Mage", "icon": "Mage-Frost", "itemLevel": 626.5,
+		 "total": 100000000, "activeTime": 198000,
+		 "abilities": [{"name": "Frostbolt", "guid": 116, "type": 16, "abilityIcon": "spell_frost_frostbolt02.jpg", "total": 60000000}]},
+		{"name": "Aradin", "id": 1, "guid": 1, "type": "Paladin", "icon": "Paladin-Holy", "timestamp": 53000, "fight": 4,
+		 "killingBlow": {"name": "Stomp", "guid": 434697, "type": 1, "abilityIcon": "ability_warstomp.jpg"}}
+	]}}`
+
+	ts := &ReportTableScalar{}
+	if err := json.Unmarshal([]byte(raw), ts); err != nil {
+		t.Fatal(err)
+	}
+
+	dto := ts.DTO()
+
+	assert.Equal(t, uint64(200000), dto.TotalTimeMS)
+	assert.Len(t, dto.Entries, 2)
+	assert.Equal(t, 500000.0, *dto.Entries[0].PerSecond)
+	assert.Equal(t, "Frostbolt", dto.Entries[0].Abilities[0].Name)
+	assert.Nil(t, dto.Entries[1].PerSecond)
+	assert.Equal(t, "Stomp", dto.Entries[1].KillingBlow.Name)
+	assert.Equal(t, uint64(53000), *dto.Entries[1].Timestamp)
+}
+
+func TestGuildReportsQuery_ToDTO(t *testing.T) {
+	grq := &GuildReportsQuery{
+		ReportData: ReportsData{
+			Reports: &ReportPagination{
+				Data: []ReportInfo{
+					{Code: "abcdEFGH1234ijkl", Title: "Raid Night", StartTime: 1700000000000, EndTime: 1700003600000},
+				},
+				Total:        101,
+				PerPage:      100,
+				CurrentPage:  1,
+				LastPage:     2,
+				HasMorePages: true,
+			},
+		},
+	}
+
+	dto := grq.ToDTO()
+
+	assert.Len(t, dto.Reports, 1)
+	assert.Equal(t, "abcdEFGH1234ijkl", dto.Reports[0].Code)
+	assert.Equal(t, uint64(3600000), dto.Reports[0].DurationMS)
+	assert.Equal(t, 101, dto.Total)
+	assert.Equal(t, 2, dto.LastPage)
+	assert.True(t, dto.HasMorePages)
+
+	assert.Empty(t, (&GuildReportsQuery{}).ToDTO().Reports)
+}
+
+func TestEncounterRankingsQuery_ToDTO(t *testing.T) {
+	raw := `{"bestAmount": 1250000.5, "medianPerformance": 87.2, "totalKills": 2, "difficulty": 5, "metric": "dps",
+		"ranks": [{"lockedIn": true, "rankPercent": 95.1, "historicalPercent": 93.4, "startTime": 1700000000000,
+		"duration": 312000, "amount": 1250000.5, "bracketData": 626.5, "spec": "Frost", "bestSpec": "Frost",
+		"report": {"code": "abcdEFGH1234ijkl", "fightID": 12, "startTime": 1699999000000},
+		"guild": {"id": 1, "name": "Heckin", "faction": 1}}]}`
+
+	er := &EncounterRanking{}
+	if err := json.Unmarshal([]byte(raw), er); err != nil {
+		t.Fatal(err)
+	}
+
+	erq := &EncounterRankingsQuery{
+		CharacterData: CharacterEncounterData{
+			Character: &CharacterEncounter{
+				Hidden:            boolean(false),
+				EncounterRankings: er,
+			},
+		},
+	}
+
+	dto := erq.ToDTO()
+
+	assert.False(t, *dto.Hidden)
+	assert.Equal(t, 2, dto.EncounterRankings.TotalKills)
+	assert.Len(t, dto.EncounterRankings.Ranks, 1)
+
+	rank := dto.EncounterRankings.Ranks[0]
+	assert.True(t, rank.LockedIn)
+	assert.Equal(t, 626.5, *rank.ItemLevel)
+	assert.Equal(t, "Heckin", rank.Guild)
+	assert.Equal(t, "https://www.warcraftlogs.com/reports/abcdEFGH1234ijkl#fight=12", rank.Report.URL)
+
+	assert.Nil(t, (&EncounterRankingsQuery{}).ToDTO().EncounterRankings)
+}
+
+func TestWorldEncounterCharacterRankingsQuery_ToDTO(t *testing.T) {
+	raw := `{"page": 2, "hasMorePages": true, "count": 100, "rankings": [
+		{"name": "Skxyz", "class": "Mage", "spec": "Frost", "amount": 1400000.25, "duration": 301000,
+		 "startTime": 1700000000000, "bracketData": 639,
+		 "report": {"code": "abcdEFGH1234ijkl", "fightID": 3, "startTime": 1699999000000},
+		 "guild": {"id": 7, "name": "Heckin", "faction": 1},
+		 "server": {"id": 3, "name": "Illidan", "region": "US"}}
+	]}`
+
+	page := &CharacterRankingsPage{}
+	if err := json.Unmarshal([]byte(raw), page); err != nil {
+		t.Fatal(err)
+	}
+
+	wcq := &WorldEncounterCharacterRankingsQuery{
+		WorldData: WorldEncounterCharacterRankingsData{
+			Encounter: &EncounterCharacterRankings{
+				Encounter:         Encounter{ID: 2902, Name: "Ulgrax the Devourer"},
+				CharacterRankings: page,
+			},
+		},
+	}
+
+	dto := wcq.ToDTO()
+
+	assert.Equal(t, 2902, dto.Encounter.ID)
+	assert.True(t, dto.HasMorePages)
+	assert.Len(t, dto.Rankings, 1)
+
+	rank := dto.Rankings[0]
+	assert.Equal(t, 101, rank.Rank)
+	assert.Equal(t, "Frost", rank.Spec)
+	assert.Equal(t, "Illidan", rank.Server)
+	assert.Equal(t, "US", rank.Region)
+	assert.Equal(t, "https://www.warcraftlogs.com/reports/abcdEFGH1234ijkl#fight=3", rank.Report.URL)
+}
+
+func TestCharacterParsesQuery_ToDTO_Summary(t *testing.T) {
+	raw := `{"bestPerformanceAverage": 82.5, "medianPerformanceAverage": 61.2, "difficulty": 5, "metric": "dps",
+		"allStars": [
+			{"partition": 1, "spec": "Frost", "points": 480.5, "possiblePoints": 960, "rank": 812},
+			{"partition": 1, "spec": "Fire", "points": 120, "possiblePoints": 960, "rank": 20412}
+		],
+		"rankings": [
+			{"encounter": {"id": 2902, "name": "Ulgrax the Devourer"}, "rankPercent": 95.1, "medianPercent": 80.2,
+			 "lockedIn": true, "totalKills": 6, "spec": "Frost", "allStars": {"points": 120.5, "possiblePoints": 120}},
+			{"encounter": {"id": 2917, "name": "The Bloodbound Horror"}, "rankPercent": 70, "medianPercent": 42.3,
+			 "lockedIn": false, "totalKills": 1, "spec": "Frost", "allStars": {"points": 60, "possiblePoints": 120}},
+			{"encounter": {"id": 2898, "name": "Sikran"}, "lockedIn": false, "totalKills": 0}
+		]}`
+
+	zr := &ZoneRanking{}
+	if err := json.Unmarshal([]byte(raw), zr); err != nil {
+		t.Fatal(err)
+	}
+
+	cpq := &CharacterParsesQuery{
+		CharacterData: CharacterData{
+			Character: &Character{ZoneRankings: zr},
+		},
+	}
+
+	summary := cpq.ToDTO().Summary
+
+	assert.Equal(t, 82.5, *summary.BestPerformanceAverage)
+	assert.Len(t, summary.Bosses, 3)
+	assert.Equal(t, 95.1, *summary.Bosses[0].Best)
+	assert.Equal(t, 80.2, *summary.Bosses[0].Median)
+	assert.Nil(t, summary.Bosses[2].Best)
+	assert.Equal(t, 2, summary.KilledBosses)
+	assert.Equal(t, 1, summary.LockedInBosses)
+	assert.False(t, summary.LockedIn)
+
+	assert.Equal(t, &AllStarTotalsDTO{Points: 180.5, PossiblePoints: 240, Spec: "Frost", Rank: 812}, summary.AllStars)
+
+	assert.Nil(t, (&CharacterParsesQuery{}).ToDTO().Summary)
+}
@@ -0,0 +1,192 @@
+package wl
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/heckin-dev/amashan/pkg/limiter"
+	"os"
+	"slices"
+	"time"
+)
+
+// DefaultCatalogRefreshInterval is how often the ExpansionCatalog is refreshed when WL_CATALOG_REFRESH_INTERVAL
+// isn't set.
+const DefaultCatalogRefreshInterval = 6 * time.Hour
+
+// catalogRefreshTimeout bounds a single refresh of the ExpansionCatalog.
+const catalogRefreshTimeout = 30 * time.Second
+
+// CatalogEntry names an expansion or zone in a CatalogDiff.
+type CatalogEntry struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CatalogDiff is what changed between two ExpansionCatalogs.
+type CatalogDiff struct {
+	AddedExpansions   []CatalogEntry `json:"added_expansions"`
+	RemovedExpansions []CatalogEntry `json:"removed_expansions"`
+	AddedZones        []CatalogEntry `json:"added_zones"`
+	RemovedZones      []CatalogEntry `json:"removed_zones"`
+	// ChangedPartitions are zones whose partitions, or default partition, changed.
+	ChangedPartitions []CatalogEntry `json:"changed_partitions"`
+	// ChangedEncounters are zones whose encounters changed.
+	ChangedEncounters []CatalogEntry `json:"changed_encounters"`
+}
+
+// Empty reports whether nothing changed.
+func (d *CatalogDiff) Empty() bool {
+	return len(d.AddedExpansions) == 0 && len(d.RemovedExpansions) == 0 &&
+		len(d.AddedZones) == 0 && len(d.RemovedZones) == 0 &&
+		len(d.ChangedPartitions) == 0 && len(d.ChangedEncounters) == 0
+}
+
+// logArgs are the key/value pairs of the non-empty parts of the diff, for logging.
+func (d *CatalogDiff) logArgs() []any {
+	var args []any
+	for _, part := range []struct {
+		key     string
+		entries []CatalogEntry
+	}{
+		{"added_expansions", d.AddedExpansions},
+		{"removed_expansions", d.RemovedExpansions},
+		{"added_zones", d.AddedZones},
+		{"removed_zones", d.RemovedZones},
+		{"changed_partitions", d.ChangedPartitions},
+		{"changed_encounters", d.ChangedEncounters},
+	} {
+		if len(part.entries) > 0 {
+			args = append(args, part.key, part.entries)
+		}
+	}
+
+	return args
+}
+
+// DiffExpansionCatalogs works out what changed from the previous catalog to the next.
+func DiffExpansionCatalogs(previous, next *ExpansionCatalog) *CatalogDiff {
+	diff := &CatalogDiff{}
+
+	for _, e := range next.Expansions() {
+		if _, err := previous.Expansion(e.ID); err != nil {
+			diff.AddedExpansions = append(diff.AddedExpansions, CatalogEntry{ID: e.ID, Name: e.Name})
+		}
+	}
+	for _, e := range previous.Expansions() {
+		if _, err := next.Expansion(e.ID); err != nil {
+			diff.RemovedExpansions = append(diff.RemovedExpansions, CatalogEntry{ID: e.ID, Name: e.Name})
+		}
+	}
+
+	for _, id := range next.zoneIDs() {
+		zone := next.zones[id]
+		entry := CatalogEntry{ID: zone.ID, Name: zone.Name}
+
+		was, err := previous.Zone(id)
+		if err != nil {
+			diff.AddedZones = append(diff.AddedZones, entry)
+			continue
+		}
+
+		if !slices.Equal(was.Partitions, zone.Partitions) {
+			diff.ChangedPartitions = append(diff.ChangedPartitions, entry)
+		}
+		if !slices.Equal(was.Encounters, zone.Encounters) {
+			diff.ChangedEncounters = append(diff.ChangedEncounters, entry)
+		}
+	}
+	for _, id := range previous.zoneIDs() {
+		if _, err := next.Zone(id); err != nil {
+			zone := previous.zones[id]
+			diff.RemovedZones = append(diff.RemovedZones, CatalogEntry{ID: zone.ID, Name: zone.Name})
+		}
+	}
+
+	return diff
+}
+
+// CatalogRefresher re-queries the ExpansionCatalog on an interval, so new zones & partitions are picked up without a
+// restart.
+type CatalogRefresher struct {
+	l hclog.Logger
+
+	client   *WarcraftLogsClient
+	interval time.Duration
+	onChange []func(diff *CatalogDiff)
+}
+
+// OnChange registers fn to be called after a refresh swapped in a catalog that differs from the previous one.
+func (r *CatalogRefresher) OnChange(fn func(diff *CatalogDiff)) {
+	r.onChange = append(r.onChange, fn)
+}
+
+// Run refreshes the catalog every interval until the context is done. It returns immediately when the interval isn't
+// positive.
+func (r *CatalogRefresher) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		r.l.Info("CatalogRefresher is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Refresh(ctx); err != nil {
+				r.l.Error("CatalogRefresher failed to refresh the expansion catalog", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh makes a single refresh of the catalog, as a Background query, logging & notifying of any changes. Nothing
+// is refreshed until the client is ready.
+func (r *CatalogRefresher) Refresh(ctx context.Context) (*CatalogDiff, error) {
+	if !r.client.Ready() {
+		return &CatalogDiff{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(limiter.WithPriority(ctx, limiter.Background), catalogRefreshTimeout)
+	defer cancel()
+
+	diff, err := r.client.RefreshExpansionCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if diff.Empty() {
+		r.l.Debug("CatalogRefresher found no changes")
+		return diff, nil
+	}
+
+	r.l.Info("CatalogRefresher swapped in a changed expansion catalog", diff.logArgs()...)
+	for _, fn := range r.onChange {
+		fn(diff)
+	}
+
+	return diff, nil
+}
+
+// NewCatalogRefresher creates a CatalogRefresher for the client, refreshing every WL_CATALOG_REFRESH_INTERVAL. An
+// interval of 0 disables refreshing.
+func NewCatalogRefresher(l hclog.Logger, client *WarcraftLogsClient) *CatalogRefresher {
+	interval := DefaultCatalogRefreshInterval
+	if v := os.Getenv("WL_CATALOG_REFRESH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			l.Error("WL_CATALOG_REFRESH_INTERVAL is not a duration, using the default", "value", v, "default", interval)
+		} else {
+			interval = d
+		}
+	}
+
+	return &CatalogRefresher{
+		l:        l,
+		client:   client,
+		interval: interval,
+	}
+}
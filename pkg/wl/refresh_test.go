@@ -0,0 +1,93 @@
+package wl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffExpansionCatalogs(t *testing.T) {
+	next := NewExpansionCatalog([]*PartitionedExpansion{
+		{
+			ID:   6,
+			Name: "The War Within",
+			Zones: []ExpansionZone{
+				{
+					ID:         38,
+					Name:       "Nerub-ar Palace",
+					Encounters: []ZoneEncounter{{ID: 2902, Name: "Ulgrax the Devourer"}},
+					Partitions: []ZonePartition{{ID: 1}, {ID: 2, Default: true}},
+				},
+				{ID: 39, Name: "Mythic+ Season 1"},
+				{ID: 42, Name: "Liberation of Undermine"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		previous *ExpansionCatalog
+		want     *CatalogDiff
+	}{
+		{
+			name:     "unchanged",
+			previous: next,
+			want:     &CatalogDiff{},
+		},
+		{
+			name: "changed",
+			previous: NewExpansionCatalog([]*PartitionedExpansion{
+				{
+					ID:   5,
+					Name: "Dragonflight",
+					Zones: []ExpansionZone{
+						{ID: 35, Name: "Amirdrassil"},
+					},
+				},
+				{
+					ID:   6,
+					Name: "The War Within",
+					Zones: []ExpansionZone{
+						{
+							ID:         38,
+							Name:       "Nerub-ar Palace",
+							Partitions: []ZonePartition{{ID: 1, Default: true}},
+						},
+						{ID: 39, Name: "Mythic+ Season 1"},
+					},
+				},
+			}),
+			want: &CatalogDiff{
+				RemovedExpansions: []CatalogEntry{{ID: 5, Name: "Dragonflight"}},
+				AddedZones:        []CatalogEntry{{ID: 42, Name: "Liberation of Undermine"}},
+				RemovedZones:      []CatalogEntry{{ID: 35, Name: "Amirdrassil"}},
+				ChangedPartitions: []CatalogEntry{{ID: 38, Name: "Nerub-ar Palace"}},
+				ChangedEncounters: []CatalogEntry{{ID: 38, Name: "Nerub-ar Palace"}},
+			},
+		},
+		{
+			name:     "from empty",
+			previous: NewExpansionCatalog(nil),
+			want: &CatalogDiff{
+				AddedExpansions: []CatalogEntry{{ID: 6, Name: "The War Within"}},
+				AddedZones: []CatalogEntry{
+					{ID: 38, Name: "Nerub-ar Palace"},
+					{ID: 39, Name: "Mythic+ Season 1"},
+					{ID: 42, Name: "Liberation of Undermine"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffExpansionCatalogs(tt.previous, next)
+			assert.Equal(t, tt.want, diff)
+			assert.Equal(t, tt.want.Empty(), diff.Empty())
+		})
+	}
+}
+
+func TestCatalogDiff_Empty(t *testing.T) {
+	assert.True(t, (&CatalogDiff{}).Empty())
+	assert.False(t, (&CatalogDiff{ChangedPartitions: []CatalogEntry{{ID: 38}}}).Empty())
+}
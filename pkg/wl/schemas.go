@@ -2,6 +2,7 @@ package wl
 
 import (
 	"github.com/hasura/go-graphql-client"
+	"time"
 )
 
 type RateLimitData struct {
@@ -26,6 +27,7 @@ type Expansion struct {
 type Zone struct {
 	ID         graphql.Int
 	Name       graphql.String
+	Frozen     graphql.Boolean
 	Encounters []Encounter
 	Partitions []Partition
 }
@@ -87,7 +89,87 @@ type CharacterData struct {
 
 type Character struct {
 	Hidden       *graphql.Boolean
-	ZoneRankings *ZoneRanking `graphql:"zoneRankings(zoneID: $zone_id, partition: $partition)" scalar:"true"`
+	ZoneRankings *ZoneRanking `graphql:"zoneRankings(zoneID: $zone_id, partition: $partition, metric: $metric, difficulty: $difficulty, size: $size, role: $role, className: $class_name, specName: $spec_name, timeframe: $timeframe)" scalar:"true"`
+}
+
+type CharacterEncounterData struct {
+	Character *CharacterEncounter `graphql:"character(name: $name, serverSlug: $server_slug, serverRegion: $server_region)"`
+}
+
+type CharacterEncounter struct {
+	Hidden            *graphql.Boolean
+	EncounterRankings *EncounterRanking `graphql:"encounterRankings(encounterID: $encounter_id, partition: $partition, metric: $metric, difficulty: $difficulty, size: $size, role: $role, className: $class_name, specName: $spec_name, timeframe: $timeframe)" scalar:"true"`
+}
+
+type EncounterRanking struct {
+	BestAmount         float64          `json:"bestAmount"`
+	MedianPerformance  *float64         `json:"medianPerformance"`
+	AveragePerformance *float64         `json:"averagePerformance"`
+	TotalKills         int              `json:"totalKills"`
+	FastestKill        *uint64          `json:"fastestKill"`
+	Difficulty         *int             `json:"difficulty"`
+	Metric             *string          `json:"metric"`
+	Partition          *int             `json:"partition"`
+	Zone               *int             `json:"zone"`
+	Ranks              []*EncounterRank `json:"ranks"`
+}
+
+func (r EncounterRanking) DTO() *EncounterRankingDTO {
+	dto := &EncounterRankingDTO{
+		BestAmount:         r.BestAmount,
+		MedianPerformance:  r.MedianPerformance,
+		AveragePerformance: r.AveragePerformance,
+		TotalKills:         r.TotalKills,
+		FastestKill:        r.FastestKill,
+		Difficulty:         r.Difficulty,
+		Metric:             r.Metric,
+		Partition:          r.Partition,
+		Zone:               r.Zone,
+		Ranks:              []*EncounterRankDTO{},
+	}
+
+	for _, rank := range r.Ranks {
+		dto.Ranks = append(dto.Ranks, rank.DTO())
+	}
+
+	return dto
+}
+
+type EncounterRank struct {
+	LockedIn          bool           `json:"lockedIn"`
+	RankPercent       *float64       `json:"rankPercent"`
+	HistoricalPercent *float64       `json:"historicalPercent"`
+	TodayPercent      *float64       `json:"todayPercent"`
+	StartTime         int64          `json:"startTime"`
+	Duration          uint64         `json:"duration"`
+	Amount            float64        `json:"amount"`
+	BracketData       *float64       `json:"bracketData"`
+	Spec              string         `json:"spec"`
+	BestSpec          string         `json:"bestSpec"`
+	Report            *RankingReport `json:"report"`
+	Guild             *RankingGuild  `json:"guild"`
+}
+
+func (r *EncounterRank) DTO() *EncounterRankDTO {
+	dto := &EncounterRankDTO{
+		LockedIn:          r.LockedIn,
+		RankPercent:       r.RankPercent,
+		HistoricalPercent: r.HistoricalPercent,
+		TodayPercent:      r.TodayPercent,
+		StartTime:         time.UnixMilli(r.StartTime).UTC(),
+		DurationMS:        r.Duration,
+		Amount:            r.Amount,
+		ItemLevel:         r.BracketData,
+		Spec:              r.Spec,
+		BestSpec:          r.BestSpec,
+		Report:            r.Report.DTO(),
+	}
+
+	if r.Guild != nil {
+		dto.Guild = r.Guild.Name
+	}
+
+	return dto
 }
 
 type ZoneRanking struct {
@@ -182,3 +264,507 @@ func (r Ranking) DTO() *RankingDTO {
 
 	return dto
 }
+
+type ReportData struct {
+	Report *Report `graphql:"report(code: $code)"`
+}
+
+type ReportFightData struct {
+	Report *ReportWithFight `graphql:"report(code: $code)"`
+}
+
+type ReportInfo struct {
+	Code       graphql.String
+	Title      graphql.String
+	StartTime  graphql.Float
+	EndTime    graphql.Float
+	Owner      *ReportOwner
+	Zone       *ReportZone
+	MasterData *ReportMasterData
+}
+
+func (r ReportInfo) DTO() ReportInfoDTO {
+	dto := ReportInfoDTO{
+		Code:       string(r.Code),
+		Title:      string(r.Title),
+		StartTime:  time.UnixMilli(int64(r.StartTime)).UTC(),
+		EndTime:    time.UnixMilli(int64(r.EndTime)).UTC(),
+		DurationMS: uint64(r.EndTime - r.StartTime),
+	}
+
+	if r.Owner != nil {
+		dto.Owner = &ReportOwnerDTO{
+			ID:   int(r.Owner.ID),
+			Name: string(r.Owner.Name),
+		}
+	}
+
+	if r.Zone != nil {
+		dto.Zone = &ZoneDTO{
+			ID:   int(r.Zone.ID),
+			Name: string(r.Zone.Name),
+		}
+	}
+
+	return dto
+}
+
+// Players returns the player actors of the report by their ID.
+func (r ReportInfo) Players() map[int]*ReportActorDTO {
+	players := map[int]*ReportActorDTO{}
+	if r.MasterData == nil {
+		return players
+	}
+
+	for _, a := range r.MasterData.Actors {
+		players[int(a.ID)] = a.DTO()
+	}
+
+	return players
+}
+
+type Report struct {
+	ReportInfo
+	Fights []ReportFight
+}
+
+type ReportWithFight struct {
+	ReportInfo
+	Fights []ReportFight `graphql:"fights(fightIDs: $fight_ids)"`
+}
+
+type ReportOwner struct {
+	ID   graphql.Int
+	Name graphql.String
+}
+
+type ReportZone struct {
+	ID   graphql.Int
+	Name graphql.String
+}
+
+type ReportMasterData struct {
+	Actors []ReportActor `graphql:"actors(type: \"Player\")"`
+}
+
+type ReportActor struct {
+	ID      graphql.Int
+	GameID  graphql.Float `graphql:"gameID"`
+	Name    graphql.String
+	Server  *graphql.String
+	Type    graphql.String
+	SubType graphql.String
+}
+
+func (a ReportActor) DTO() *ReportActorDTO {
+	dto := &ReportActorDTO{
+		ID:     int(a.ID),
+		GameID: int(a.GameID),
+		Name:   string(a.Name),
+		Class:  string(a.SubType),
+	}
+
+	if a.Server != nil {
+		dto.Server = string(*a.Server)
+	}
+
+	return dto
+}
+
+type ReportFight struct {
+	ID               graphql.Int
+	EncounterID      graphql.Int `graphql:"encounterID"`
+	Name             graphql.String
+	StartTime        graphql.Float
+	EndTime          graphql.Float
+	Kill             *graphql.Boolean
+	Difficulty       *graphql.Int
+	Size             *graphql.Int
+	BossPercentage   *graphql.Float
+	FightPercentage  *graphql.Float
+	AverageItemLevel *graphql.Float
+	FriendlyPlayers  []graphql.Int
+}
+
+// DTO converts the fight, resolving the friendly players from the given actors when provided.
+func (f ReportFight) DTO(players map[int]*ReportActorDTO) *ReportFightDTO {
+	dto := &ReportFightDTO{
+		ID:          int(f.ID),
+		EncounterID: int(f.EncounterID),
+		Name:        string(f.Name),
+		StartTime:   uint64(f.StartTime),
+		EndTime:     uint64(f.EndTime),
+		DurationMS:  uint64(f.EndTime - f.StartTime),
+	}
+
+	if f.Kill != nil {
+		dto.Kill = Bool(bool(*f.Kill))
+	}
+
+	if f.Difficulty != nil {
+		dto.Difficulty = Int(int(*f.Difficulty))
+	}
+
+	if f.Size != nil {
+		dto.Size = Int(int(*f.Size))
+	}
+
+	if f.BossPercentage != nil {
+		dto.BossPercentage = Float(float64(*f.BossPercentage))
+	}
+
+	if f.FightPercentage != nil {
+		dto.FightPercentage = Float(float64(*f.FightPercentage))
+	}
+
+	if f.AverageItemLevel != nil {
+		dto.AverageItemLevel = Float(float64(*f.AverageItemLevel))
+	}
+
+	if players != nil {
+		for _, id := range f.FriendlyPlayers {
+			if p, ok := players[int(id)]; ok {
+				dto.Players = append(dto.Players, p)
+			}
+		}
+	}
+
+	return dto
+}
+
+type ReportTableData struct {
+	Report *ReportTable `graphql:"report(code: $code)"`
+}
+
+type ReportTable struct {
+	Table *ReportTableScalar `graphql:"table(dataType: $data_type, fightIDs: $fight_ids, sourceID: $source_id, abilityID: $ability_id)" scalar:"true"`
+}
+
+type ReportTableScalar struct {
+	Data struct {
+		TotalTime float64       `json:"totalTime"`
+		Entries   []*TableEntry `json:"entries"`
+	} `json:"data"`
+}
+
+func (t ReportTableScalar) DTO() *ReportTableDTO {
+	dto := &ReportTableDTO{
+		TotalTimeMS: uint64(t.Data.TotalTime),
+	}
+
+	for _, e := range t.Data.Entries {
+		entry := e.DTO()
+		if t.Data.TotalTime > 0 && e.Total > 0 {
+			entry.PerSecond = Float(e.Total / (t.Data.TotalTime / 1000))
+		}
+		dto.Entries = append(dto.Entries, entry)
+	}
+
+	return dto
+}
+
+type TableEntry struct {
+	ID          int             `json:"id"`
+	GUID        int64           `json:"guid"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Icon        string          `json:"icon"`
+	ItemLevel   *float64        `json:"itemLevel"`
+	Total       float64         `json:"total"`
+	ActiveTime  *float64        `json:"activeTime"`
+	Timestamp   *float64        `json:"timestamp"`
+	Fight       *int            `json:"fight"`
+	KillingBlow *TableAbility   `json:"killingBlow"`
+	Abilities   []*TableAbility `json:"abilities"`
+}
+
+func (e TableEntry) DTO() *TableEntryDTO {
+	dto := &TableEntryDTO{
+		ID:        e.ID,
+		GUID:      e.GUID,
+		Name:      e.Name,
+		Type:      e.Type,
+		Icon:      e.Icon,
+		ItemLevel: e.ItemLevel,
+		Total:     e.Total,
+		Fight:     e.Fight,
+	}
+
+	if e.ActiveTime != nil {
+		activeTime := uint64(*e.ActiveTime)
+		dto.ActiveTimeMS = &activeTime
+	}
+
+	if e.Timestamp != nil {
+		timestamp := uint64(*e.Timestamp)
+		dto.Timestamp = &timestamp
+	}
+
+	if e.KillingBlow != nil {
+		dto.KillingBlow = e.KillingBlow.DTO()
+	}
+
+	for _, a := range e.Abilities {
+		dto.Abilities = append(dto.Abilities, a.DTO())
+	}
+
+	return dto
+}
+
+type TableAbility struct {
+	GUID        int64   `json:"guid"`
+	Name        string  `json:"name"`
+	AbilityIcon string  `json:"abilityIcon"`
+	Total       float64 `json:"total"`
+}
+
+func (a TableAbility) DTO() *TableAbilityDTO {
+	return &TableAbilityDTO{
+		GUID:  a.GUID,
+		Name:  a.Name,
+		Icon:  a.AbilityIcon,
+		Total: a.Total,
+	}
+}
+
+type ReportGraphData struct {
+	Report *ReportGraph `graphql:"report(code: $code)"`
+}
+
+type ReportGraph struct {
+	Graph *ReportGraphScalar `graphql:"graph(dataType: $data_type, fightIDs: $fight_ids, sourceID: $source_id, abilityID: $ability_id)" scalar:"true"`
+}
+
+type ReportGraphScalar struct {
+	Data struct {
+		Series []*GraphSeries `json:"series"`
+	} `json:"data"`
+}
+
+func (g ReportGraphScalar) DTO() *ReportGraphDTO {
+	dto := &ReportGraphDTO{}
+	for _, s := range g.Data.Series {
+		dto.Series = append(dto.Series, s.DTO())
+	}
+
+	return dto
+}
+
+type GraphSeries struct {
+	ID            int       `json:"id"`
+	GUID          int64     `json:"guid"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`
+	PointStart    float64   `json:"pointStart"`
+	PointInterval float64   `json:"pointInterval"`
+	Total         float64   `json:"total"`
+	Data          []float64 `json:"data"`
+}
+
+func (s GraphSeries) DTO() *GraphSeriesDTO {
+	return &GraphSeriesDTO{
+		ID:              s.ID,
+		GUID:            s.GUID,
+		Name:            s.Name,
+		Type:            s.Type,
+		PointStartMS:    uint64(s.PointStart),
+		PointIntervalMS: uint64(s.PointInterval),
+		Total:           s.Total,
+		Points:          s.Data,
+	}
+}
+
+type ReportEventsData struct {
+	Report *ReportEvents `graphql:"report(code: $code)"`
+}
+
+type ReportEvents struct {
+	Events *ReportEventPaginator `graphql:"events(dataType: $data_type, fightIDs: $fight_ids, startTime: $start_time, endTime: $end_time, limit: $limit)"`
+}
+
+type ReportEventPaginator struct {
+	Data              []*ReportEvent `scalar:"true"`
+	NextPageTimestamp *graphql.Float
+}
+
+// ReportEvent is a single event from a report, only the fields of the damage & death events are kept.
+type ReportEvent struct {
+	Timestamp            float64 `json:"timestamp"`
+	Type                 string  `json:"type"`
+	Fight                int     `json:"fight"`
+	SourceID             int     `json:"sourceID"`
+	TargetID             int     `json:"targetID"`
+	AbilityGameID        int     `json:"abilityGameID"`
+	Amount               float64 `json:"amount"`
+	Overkill             float64 `json:"overkill"`
+	KillerID             *int    `json:"killerID"`
+	KillingAbilityGameID *int    `json:"killingAbilityGameID"`
+}
+
+type ReportAbilitiesData struct {
+	Report *ReportAbilities `graphql:"report(code: $code)"`
+}
+
+type ReportAbilities struct {
+	MasterData *struct {
+		Abilities []ReportAbility
+	}
+}
+
+type ReportAbility struct {
+	GameID graphql.Float `graphql:"gameID"`
+	Name   graphql.String
+	Icon   graphql.String
+}
+
+type ReportsData struct {
+	Reports *ReportPagination `graphql:"reports(guildName: $guild_name, guildServerSlug: $guild_server_slug, guildServerRegion: $guild_server_region, zoneID: $zone_id, startTime: $start_time, endTime: $end_time, page: $page, limit: $limit)"`
+}
+
+type ReportPagination struct {
+	Data         []ReportInfo
+	Total        graphql.Int
+	PerPage      graphql.Int     `graphql:"per_page"`
+	CurrentPage  graphql.Int     `graphql:"current_page"`
+	LastPage     graphql.Int     `graphql:"last_page"`
+	HasMorePages graphql.Boolean `graphql:"has_more_pages"`
+}
+
+type WorldEncounterCharacterRankingsData struct {
+	Encounter *EncounterCharacterRankings `graphql:"encounter(id: $encounter_id)"`
+}
+
+type EncounterCharacterRankings struct {
+	Encounter
+	CharacterRankings *CharacterRankingsPage `graphql:"characterRankings(serverRegion: $server_region, className: $class_name, specName: $spec_name, metric: $metric, difficulty: $difficulty, size: $size, partition: $partition, page: $page)" scalar:"true"`
+}
+
+type WorldEncounterFightRankingsData struct {
+	Encounter *EncounterFightRankings `graphql:"encounter(id: $encounter_id)"`
+}
+
+type EncounterFightRankings struct {
+	Encounter
+	FightRankings *FightRankingsPage `graphql:"fightRankings(serverRegion: $server_region, metric: $metric, difficulty: $difficulty, size: $size, partition: $partition, page: $page)" scalar:"true"`
+}
+
+type CharacterRankingsPage struct {
+	Page         int                      `json:"page"`
+	HasMorePages bool                     `json:"hasMorePages"`
+	Count        int                      `json:"count"`
+	Rankings     []*WorldCharacterRanking `json:"rankings"`
+}
+
+type FightRankingsPage struct {
+	Page         int                  `json:"page"`
+	HasMorePages bool                 `json:"hasMorePages"`
+	Count        int                  `json:"count"`
+	Rankings     []*WorldFightRanking `json:"rankings"`
+}
+
+// RankingReport is the report & fight a world ranking was set in.
+type RankingReport struct {
+	Code    string `json:"code"`
+	FightID int    `json:"fightID"`
+}
+
+type RankingGuild struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type RankingServer struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+type WorldCharacterRanking struct {
+	Name        string         `json:"name"`
+	Class       string         `json:"class"`
+	Spec        string         `json:"spec"`
+	Amount      float64        `json:"amount"`
+	Duration    uint64         `json:"duration"`
+	StartTime   int64          `json:"startTime"`
+	BracketData *float64       `json:"bracketData"`
+	Report      *RankingReport `json:"report"`
+	Guild       *RankingGuild  `json:"guild"`
+	Server      *RankingServer `json:"server"`
+}
+
+func (r *WorldCharacterRanking) DTO(rank int) *WorldCharacterRankDTO {
+	dto := &WorldCharacterRankDTO{
+		Rank:       rank,
+		Name:       r.Name,
+		Class:      r.Class,
+		Spec:       r.Spec,
+		Amount:     r.Amount,
+		DurationMS: r.Duration,
+		StartTime:  time.UnixMilli(r.StartTime).UTC(),
+		ItemLevel:  r.BracketData,
+		Report:     r.Report.DTO(),
+	}
+
+	if r.Guild != nil {
+		dto.Guild = r.Guild.Name
+	}
+
+	if r.Server != nil {
+		dto.Server = r.Server.Name
+		dto.Region = r.Server.Region
+	}
+
+	return dto
+}
+
+type WorldFightRanking struct {
+	Duration    uint64         `json:"duration"`
+	StartTime   int64          `json:"startTime"`
+	Deaths      *int           `json:"deaths"`
+	Tanks       *int           `json:"tanks"`
+	Healers     *int           `json:"healers"`
+	Melee       *int           `json:"melee"`
+	Ranged      *int           `json:"ranged"`
+	BracketData *float64       `json:"bracketData"`
+	Report      *RankingReport `json:"report"`
+	Guild       *RankingGuild  `json:"guild"`
+	Server      *RankingServer `json:"server"`
+}
+
+func (r *WorldFightRanking) DTO(rank int) *WorldFightRankDTO {
+	dto := &WorldFightRankDTO{
+		Rank:       rank,
+		DurationMS: r.Duration,
+		StartTime:  time.UnixMilli(r.StartTime).UTC(),
+		Deaths:     r.Deaths,
+		Tanks:      r.Tanks,
+		Healers:    r.Healers,
+		Melee:      r.Melee,
+		Ranged:     r.Ranged,
+		ItemLevel:  r.BracketData,
+		Report:     r.Report.DTO(),
+	}
+
+	if r.Guild != nil {
+		dto.Guild = r.Guild.Name
+	}
+
+	if r.Server != nil {
+		dto.Server = r.Server.Name
+		dto.Region = r.Server.Region
+	}
+
+	return dto
+}
+
+func (r *RankingReport) DTO() *EncounterRankReportDTO {
+	if r == nil {
+		return nil
+	}
+
+	return &EncounterRankReportDTO{
+		Code:    r.Code,
+		FightID: r.FightID,
+		URL:     ReportURL(r.Code, r.FightID),
+	}
+}
@@ -31,3 +31,6 @@ var MythicKeystoneSeason []byte
 
 //go:embed data-realm-index.json
 var RealmDataIndexKR []byte
+
+//go:embed data-mythic-keystone-period-index.json
+var MythicKeystonePeriodIndex []byte
@@ -16,3 +16,30 @@ var MPlusBestRuns []byte
 
 //go:embed raiderio_all-in-one.json
 var MPlusAllInOne []byte
+
+//go:embed raiderio_profile-fields.json
+var ProfileFields []byte
+
+//go:embed raiderio_guild-profile.json
+var GuildProfile []byte
+
+//go:embed raiderio_guild-boss-kill.json
+var GuildBossKill []byte
+
+//go:embed raiderio_raiding-progression.json
+var RaidingProgression []byte
+
+//go:embed raiderio_mplus-affixes.json
+var MPlusAffixes []byte
+
+//go:embed raiderio_mplus-static-data.json
+var MPlusStaticData []byte
+
+//go:embed raiderio_mplus-season-cutoffs.json
+var MPlusSeasonCutoffs []byte
+
+//go:embed raiderio_mplus-runs.json
+var MPlusRuns []byte
+
+//go:embed raiderio_mplus-run-details.json
+var MPlusRunDetails []byte